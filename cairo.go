@@ -1,3 +1,4 @@
+//go:build !goci
 // +build !goci
 
 package cairo
@@ -18,6 +19,47 @@ func (self Status) String() string {
 	return C.GoString(C.cairo_status_to_string(C.cairo_status_t(self)))
 }
 
+// Error implements the error interface, so every Status except
+// STATUS_SUCCESS can be returned as error and compared with errors.Is.
+func (self Status) Error() string {
+	return self.String()
+}
+
+// statusError returns nil for STATUS_SUCCESS or the status as error.
+func statusError(status Status) error {
+	if status == STATUS_SUCCESS {
+		return nil
+	}
+	return status
+}
+
+// Destroyed objects point to these inert objects, so cairo functions
+// called on them are no-ops instead of crashes and the objects
+// report STATUS_NULL_POINTER. The inert objects are never destroyed.
+var (
+	nilContext    = C.cairo_create(nil)
+	nilSurface    = newNilSurface()
+	nilPattern    = C.cairo_pattern_create_for_surface(nil)
+	nilFontFace   = C.cairo_toy_font_face_create(nil, C.CAIRO_FONT_SLANT_NORMAL, C.CAIRO_FONT_WEIGHT_NORMAL)
+	nilScaledFont = newNilScaledFont()
+)
+
+// newNilScaledFont returns the error scaled font
+// that cairo creates for a font face in error state.
+func newNilScaledFont() *C.cairo_scaled_font_t {
+	var identity C.cairo_matrix_t
+	C.cairo_matrix_init_identity(&identity)
+	return C.cairo_scaled_font_create(nilFontFace, &identity, &identity, nil)
+}
+
+// newNilSurface returns a finished empty image surface,
+// drawing on it fails with STATUS_SURFACE_FINISHED.
+func newNilSurface() *C.cairo_surface_t {
+	s := C.cairo_image_surface_create(C.CAIRO_FORMAT_ARGB32, 0, 0)
+	C.cairo_surface_finish(s)
+	return s
+}
+
 const (
 	STATUS_SUCCESS Status = iota
 	STATUS_NO_MEMORY
@@ -113,6 +155,9 @@ const (
 	ANTIALIAS_NONE
 	ANTIALIAS_GRAY
 	ANTIALIAS_SUBPIXEL
+	ANTIALIAS_FAST
+	ANTIALIAS_GOOD
+	ANTIALIAS_BEST
 )
 
 // cairo_svg_unit_t
@@ -164,40 +209,50 @@ const (
 	TEXT_CLUSTER_FLAG_BACKWARD TextClusterFlag = 0x00000001
 )
 
-// cairo_font_slant_t values
+// cairo_font_slant_t
+type FontSlant int
+
 const (
-	FONT_SLANT_NORMAL = iota
+	FONT_SLANT_NORMAL FontSlant = iota
 	FONT_SLANT_ITALIC
 	FONT_SLANT_OBLIQUE
 )
 
-// cairo_font_weight_t values
+// cairo_font_weight_t
+type FontWeight int
+
 const (
-	FONT_WEIGHT_NORMAL = iota
+	FONT_WEIGHT_NORMAL FontWeight = iota
 	FONT_WEIGHT_BOLD
 )
 
-// cairo_subpixel_order_t values
+// cairo_subpixel_order_t
+type SubpixelOrder int
+
 const (
-	SUBPIXEL_ORDER_DEFAULT = iota
+	SUBPIXEL_ORDER_DEFAULT SubpixelOrder = iota
 	SUBPIXEL_ORDER_RGB
 	SUBPIXEL_ORDER_BGR
 	SUBPIXEL_ORDER_VRGB
 	SUBPIXEL_ORDER_VBGR
 )
 
-// cairo_hint_style_t values
+// cairo_hint_style_t
+type HintStyle int
+
 const (
-	HINT_STYLE_DEFAULT = iota
+	HINT_STYLE_DEFAULT HintStyle = iota
 	HINT_STYLE_NONE
 	HINT_STYLE_SLIGHT
 	HINT_STYLE_MEDIUM
 	HINT_STYLE_FULL
 )
 
-// cairo_hint_metrics_t values
+// cairo_hint_metrics_t
+type HintMetrics int
+
 const (
-	HINT_METRICS_DEFAULT = iota
+	HINT_METRICS_DEFAULT HintMetrics = iota
 	HINT_METRICS_OFF
 	HINT_METRICS_ON
 )
@@ -278,6 +333,8 @@ const (
 	PATTERN_TYPE_SURFACE
 	PATTERN_TYPE_LINEAR
 	PATTERN_TYPE_RADIAL
+	PATTERN_TYPE_MESH
+	PATTERN_TYPE_RASTER_SOURCE
 )
 
 // cairo_extend_t
@@ -363,6 +420,10 @@ const (
 
 type Pattern struct {
 	pattern *C.cairo_pattern_t
+	// C pointers are not scanned by the garbage collector, without
+	// a Go pointer the struct could be tiny allocated and then
+	// its finalizer might never run.
+	_ *byte
 }
 
 type Linear struct {
@@ -380,8 +441,11 @@ type Rectangle struct {
 	Width, Height float64
 }
 
+// TextCluster maps NumBytes bytes of UTF-8 text
+// to the NumGlyphs glyphs rendering them.
 type TextCluster struct {
-	// todo
+	NumBytes  int
+	NumGlyphs int
 }
 
 type TextExtents struct {
@@ -402,20 +466,25 @@ type FontExtents struct {
 }
 
 type FontFace struct {
-	face    *C.cairo_font_face_t
-	ft_face *C.FT_Face
+	face *C.cairo_font_face_t
+	_    *byte // not tiny allocated, see Pattern
 }
 
 type FontOptions struct {
-	// todo
+	options *C.cairo_font_options_t
+	_       *byte // not tiny allocated, see Pattern
 }
 
 type ScaledFont struct {
-	// todo
+	scaledFont *C.cairo_scaled_font_t
+	_          *byte // not tiny allocated, see Pattern
 }
 
+// Glyph is the font specific Index of a glyph
+// positioned at X, Y in user-space coordinates.
 type Glyph struct {
-	// todo
+	Index uint64
+	X, Y  float64
 }
 
 type Device struct {
@@ -0,0 +1,94 @@
+//go:build !goci
+// +build !goci
+
+package cairo
+
+// Go functions exported to C for cairo callbacks.
+// Because of the //export directives the preamble of this file
+// must only contain declarations, the C trampolines calling
+// these functions are defined in the files using them.
+
+// #include <stdint.h>
+// #include <cairo/cairo.h>
+import "C"
+
+import (
+	"image"
+	"runtime/cgo"
+	"unsafe"
+)
+
+//export goCairoWriteFunc
+func goCairoWriteFunc(closure C.uintptr_t, data *C.uchar, length C.uint) C.cairo_status_t {
+	stream := cgo.Handle(closure).Value().(*writeStream)
+	return C.cairo_status_t(stream.write(C.GoBytes(unsafe.Pointer(data), C.int(length))))
+}
+
+//export goCairoReadFunc
+func goCairoReadFunc(closure C.uintptr_t, data *C.uchar, length C.uint) C.cairo_status_t {
+	stream := cgo.Handle(closure).Value().(*readStream)
+	return C.cairo_status_t(stream.read(unsafe.Slice((*byte)(unsafe.Pointer(data)), int(length))))
+}
+
+//export goCairoDeleteHandle
+func goCairoDeleteHandle(handle C.uintptr_t) {
+	cgo.Handle(handle).Delete()
+}
+
+//export goCairoRasterSourceAcquire
+func goCairoRasterSourceAcquire(source C.uintptr_t, extents *C.cairo_rectangle_int_t) *C.cairo_surface_t {
+	rect := image.Rect(int(extents.x), int(extents.y), int(extents.x+extents.width), int(extents.y+extents.height))
+	return cgo.Handle(source).Value().(*rasterSource).acquireSurface(rect)
+}
+
+//export goCairoRasterSourceRelease
+func goCairoRasterSourceRelease(source C.uintptr_t, surface *C.cairo_surface_t) {
+	cgo.Handle(source).Value().(*rasterSource).releaseSurface(surface)
+}
+
+//export goCairoRasterSourceCopy
+func goCairoRasterSourceCopy(source C.uintptr_t) C.cairo_status_t {
+	cgo.Handle(source).Value().(*rasterSource).copy()
+	return C.CAIRO_STATUS_SUCCESS
+}
+
+//export goCairoRasterSourceFinish
+func goCairoRasterSourceFinish(source C.uintptr_t) {
+	handle := cgo.Handle(source)
+	if handle.Value().(*rasterSource).finish() {
+		handle.Delete()
+	}
+}
+
+//export goCairoUserFontInit
+func goCairoUserFontInit(funcs C.uintptr_t, scaledFont *C.cairo_scaled_font_t, cr *C.cairo_t, extents *C.cairo_font_extents_t) C.cairo_status_t {
+	return C.cairo_status_t(cgo.Handle(funcs).Value().(*UserFontFuncs).init(&ScaledFont{scaledFont: scaledFont}, &Context{context: cr}, extents))
+}
+
+//export goCairoUserFontRenderGlyph
+func goCairoUserFontRenderGlyph(funcs C.uintptr_t, scaledFont *C.cairo_scaled_font_t, glyph C.ulong, cr *C.cairo_t, extents *C.cairo_text_extents_t) C.cairo_status_t {
+	return C.cairo_status_t(cgo.Handle(funcs).Value().(*UserFontFuncs).renderGlyph(&ScaledFont{scaledFont: scaledFont}, uint64(glyph), &Context{context: cr}, extents))
+}
+
+//export goCairoUserFontUnicodeToGlyph
+func goCairoUserFontUnicodeToGlyph(funcs C.uintptr_t, scaledFont *C.cairo_scaled_font_t, unicode C.ulong, glyphIndex *C.ulong) C.cairo_status_t {
+	glyph, err := cgo.Handle(funcs).Value().(*UserFontFuncs).UnicodeToGlyph(&ScaledFont{scaledFont: scaledFont}, rune(unicode))
+	if err != nil {
+		return C.cairo_status_t(callbackStatus(err))
+	}
+	*glyphIndex = C.ulong(glyph)
+	return C.CAIRO_STATUS_SUCCESS
+}
+
+//export goCairoUserFontTextToGlyphs
+func goCairoUserFontTextToGlyphs(funcs C.uintptr_t, scaledFont *C.cairo_scaled_font_t, utf8 *C.char, utf8Len C.int, glyphs **C.cairo_glyph_t, numGlyphs *C.int, clusters **C.cairo_text_cluster_t, numClusters *C.int, clusterFlags *C.cairo_text_cluster_flags_t) C.cairo_status_t {
+	text := C.GoStringN(utf8, utf8Len)
+	return C.cairo_status_t(cgo.Handle(funcs).Value().(*UserFontFuncs).textToGlyphs(&ScaledFont{scaledFont: scaledFont}, text, glyphs, numGlyphs, clusters, numClusters, clusterFlags))
+}
+
+//export goCairoFtFaceDone
+func goCairoFtFaceDone(face C.uintptr_t) {
+	handle := cgo.Handle(face)
+	handle.Value().(*ftFace).done()
+	handle.Delete()
+}
@@ -0,0 +1,673 @@
+//go:build !goci
+// +build !goci
+
+package cairo
+
+// #include <cairo/cairo.h>
+// #include <stdlib.h>
+// #include <string.h>
+import "C"
+
+import (
+	"image/color"
+	"runtime"
+	"unsafe"
+)
+
+// Context is a cairo drawing context (cairo_t).
+// Several contexts can draw on the same Surface independently.
+type Context struct {
+	context Cairo_context
+	_       *byte // not tiny allocated, see Pattern
+}
+
+// newContext wraps c and destroys it when the Context
+// gets garbage collected without being destroyed.
+func newContext(c *C.cairo_t) *Context {
+	context := &Context{context: c}
+	runtime.SetFinalizer(context, (*Context).Destroy)
+	return context
+}
+
+// NewContext creates a new context drawing on target.
+func NewContext(target *Surface) *Context {
+	defer runtime.KeepAlive(target)
+	return newContext(C.cairo_create(target.surface))
+}
+
+// NewContextChecked is like NewContext but returns an error
+// instead of a context in an error state.
+func NewContextChecked(target *Surface) (*Context, error) {
+	context := NewContext(target)
+	if err := statusError(context.Status()); err != nil {
+		context.Destroy()
+		return nil, err
+	}
+	return context, nil
+}
+
+// NewContextFromC creates a new context from a C cairo_t.
+// The context is not destroyed automatically by the garbage collector.
+func NewContextFromC(c Cairo_context) *Context {
+	return &Context{context: c}
+}
+
+func (self *Context) Native() uintptr {
+	return uintptr(unsafe.Pointer(self.context))
+}
+
+// Reference returns a new Context for the same cairo_t
+// and increases its reference count.
+// Both Contexts have to be destroyed.
+func (self *Context) Reference() *Context {
+	defer runtime.KeepAlive(self)
+	return newContext(C.cairo_reference(self.context))
+}
+
+func (self *Context) GetReferenceCount() int {
+	defer runtime.KeepAlive(self)
+	return int(C.cairo_get_reference_count(self.context))
+}
+
+// Destroy decreases the reference count of the cairo_t.
+// It is safe to call Destroy more than once, after the first call
+// all methods are no-ops and Status returns STATUS_NULL_POINTER.
+func (self *Context) Destroy() {
+	if self.context != nilContext {
+		C.cairo_destroy(self.context)
+		self.context = nilContext
+	}
+	runtime.SetFinalizer(self, nil)
+}
+
+func (self *Context) Status() Status {
+	defer runtime.KeepAlive(self)
+	return Status(C.cairo_status(self.context))
+}
+
+// GetTarget returns the surface the context was created for.
+// The returned Surface has its own default context.
+func (self *Context) GetTarget() *Surface {
+	defer runtime.KeepAlive(self)
+	return newSurface(C.cairo_surface_reference(C.cairo_get_target(self.context)))
+}
+
+// GetGroupTarget returns the surface the context currently draws on,
+// which is the intermediate surface of the innermost group started
+// with PushGroup or the target surface if there is no group.
+// The returned Surface has its own default context.
+func (self *Context) GetGroupTarget() *Surface {
+	defer runtime.KeepAlive(self)
+	return newSurface(C.cairo_surface_reference(C.cairo_get_group_target(self.context)))
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Drawing state methods
+
+// CurrentPoint returns the current point of the current path.
+// The error is STATUS_NO_CURRENT_POINT if there is no current point,
+// or the status of the context if it is in an error state.
+func (self *Context) CurrentPoint() (x, y float64, err error) {
+	defer runtime.KeepAlive(self)
+	if err := statusError(self.Status()); err != nil {
+		return 0, 0, err
+	}
+	if !self.HasCurrentPoint() {
+		return 0, 0, STATUS_NO_CURRENT_POINT
+	}
+	C.cairo_get_current_point(self.context, (*C.double)(&x), (*C.double)(&y))
+	return x, y, nil
+}
+
+// GetCurrentPoint returns the current point of the current path,
+// or 0, 0 if there is none, see CurrentPoint.
+func (self *Context) GetCurrentPoint() (float64, float64) {
+	x, y, _ := self.CurrentPoint()
+	return x, y
+}
+
+func (self *Context) HasCurrentPoint() bool {
+	defer runtime.KeepAlive(self)
+	return C.cairo_has_current_point(self.context) != 0
+}
+
+func (self *Context) Save() {
+	defer runtime.KeepAlive(self)
+	C.cairo_save(self.context)
+}
+
+func (self *Context) Restore() {
+	defer runtime.KeepAlive(self)
+	C.cairo_restore(self.context)
+}
+
+func (self *Context) PushGroup() {
+	defer runtime.KeepAlive(self)
+	C.cairo_push_group(self.context)
+}
+
+func (self *Context) PushGroupWithContent(content Content) {
+	defer runtime.KeepAlive(self)
+	C.cairo_push_group_with_content(self.context, C.cairo_content_t(content))
+}
+
+func (self *Context) PopGroup() (pattern *Pattern) {
+	defer runtime.KeepAlive(self)
+	return newPattern(C.cairo_pop_group(self.context))
+}
+
+func (self *Context) PopGroupToSource() {
+	defer runtime.KeepAlive(self)
+	C.cairo_pop_group_to_source(self.context)
+}
+
+func (self *Context) SetOperator(operator Operator) {
+	defer runtime.KeepAlive(self)
+	C.cairo_set_operator(self.context, C.cairo_operator_t(operator))
+}
+
+func (self *Context) SetSource(pattern *Pattern) {
+	defer runtime.KeepAlive(self)
+	defer runtime.KeepAlive(pattern)
+	C.cairo_set_source(self.context, pattern.pattern)
+}
+
+func (self *Context) SetSourceRGB(red, green, blue float64) {
+	defer runtime.KeepAlive(self)
+	C.cairo_set_source_rgb(self.context, C.double(red), C.double(green), C.double(blue))
+}
+
+func (self *Context) SetSourceRGBA(red, green, blue, alpha float64) {
+	defer runtime.KeepAlive(self)
+	C.cairo_set_source_rgba(self.context, C.double(red), C.double(green), C.double(blue), C.double(alpha))
+}
+
+// SetSourceColor sets the source to an opaque or translucent color.
+func (self *Context) SetSourceColor(c color.Color) {
+	self.SetSourceRGBA(colorToRGBA(c))
+}
+
+// GetSource returns the current source pattern of the context.
+// The returned Pattern holds a new reference to the cairo pattern.
+func (self *Context) GetSource() *Pattern {
+	defer runtime.KeepAlive(self)
+	return newPattern(C.cairo_pattern_reference(C.cairo_get_source(self.context)))
+}
+
+func (self *Context) SetSourceSurface(surface *Surface, x, y float64) {
+	defer runtime.KeepAlive(self)
+	defer runtime.KeepAlive(surface)
+	C.cairo_set_source_surface(self.context, surface.surface, C.double(x), C.double(y))
+}
+
+func (self *Context) SetTolerance(tolerance float64) {
+	defer runtime.KeepAlive(self)
+	C.cairo_set_tolerance(self.context, C.double(tolerance))
+}
+
+func (self *Context) SetAntialias(antialias Antialias) {
+	defer runtime.KeepAlive(self)
+	C.cairo_set_antialias(self.context, C.cairo_antialias_t(antialias))
+}
+
+func (self *Context) SetFillRule(fill_rule FillRule) {
+	defer runtime.KeepAlive(self)
+	C.cairo_set_fill_rule(self.context, C.cairo_fill_rule_t(fill_rule))
+}
+
+func (self *Context) SetLineWidth(width float64) {
+	defer runtime.KeepAlive(self)
+	C.cairo_set_line_width(self.context, C.double(width))
+}
+
+func (self *Context) SetLineCap(line_cap LineCap) {
+	defer runtime.KeepAlive(self)
+	C.cairo_set_line_cap(self.context, C.cairo_line_cap_t(line_cap))
+}
+
+func (self *Context) SetLineJoin(line_join LineJoin) {
+	defer runtime.KeepAlive(self)
+	C.cairo_set_line_join(self.context, C.cairo_line_join_t(line_join))
+}
+
+func (self *Context) SetDash(dashes []float64, num_dashes int, offset float64) {
+	defer runtime.KeepAlive(self)
+	var dashesp *C.double
+	if len(dashes) > 0 {
+		dashesp = (*C.double)(&dashes[0])
+	}
+	C.cairo_set_dash(self.context, dashesp, C.int(num_dashes), C.double(offset))
+}
+
+func (self *Context) SetMiterLimit(limit float64) {
+	defer runtime.KeepAlive(self)
+	C.cairo_set_miter_limit(self.context, C.double(limit))
+}
+
+func (self *Context) Translate(tx, ty float64) {
+	defer runtime.KeepAlive(self)
+	C.cairo_translate(self.context, C.double(tx), C.double(ty))
+}
+
+func (self *Context) Scale(sx, sy float64) {
+	defer runtime.KeepAlive(self)
+	C.cairo_scale(self.context, C.double(sx), C.double(sy))
+}
+
+func (self *Context) Rotate(angle float64) {
+	defer runtime.KeepAlive(self)
+	C.cairo_rotate(self.context, C.double(angle))
+}
+
+func (self *Context) Transform(matrix Matrix) {
+	defer runtime.KeepAlive(self)
+	C.cairo_transform(self.context, matrix.cairo_matrix_t())
+}
+
+func (self *Context) GetMatrix() (matrix Matrix) {
+	defer runtime.KeepAlive(self)
+	C.cairo_get_matrix(self.context, (*C.cairo_matrix_t)(unsafe.Pointer(&matrix)))
+	return matrix
+}
+
+func (self *Context) SetMatrix(matrix Matrix) {
+	defer runtime.KeepAlive(self)
+	C.cairo_set_matrix(self.context, matrix.cairo_matrix_t())
+}
+
+func (self *Context) IdentityMatrix() {
+	defer runtime.KeepAlive(self)
+	C.cairo_identity_matrix(self.context)
+}
+
+func (self *Context) UserToDevice(x, y float64) (float64, float64) {
+	defer runtime.KeepAlive(self)
+	C.cairo_user_to_device(self.context, (*C.double)(&x), (*C.double)(&y))
+	return x, y
+}
+
+func (self *Context) UserToDeviceDistance(dx, dy float64) (float64, float64) {
+	defer runtime.KeepAlive(self)
+	C.cairo_user_to_device_distance(self.context, (*C.double)(&dx), (*C.double)(&dy))
+	return dx, dy
+}
+
+func (self *Context) DeviceToUser(x, y float64) (float64, float64) {
+	defer runtime.KeepAlive(self)
+	C.cairo_device_to_user(self.context, (*C.double)(&x), (*C.double)(&y))
+	return x, y
+}
+
+func (self *Context) DeviceToUserDistance(x, y float64) (float64, float64) {
+	defer runtime.KeepAlive(self)
+	C.cairo_device_to_user_distance(self.context, (*C.double)(&x), (*C.double)(&y))
+	return x, y
+}
+
+// path creation methods
+
+func (self *Context) NewPath() {
+	defer runtime.KeepAlive(self)
+	C.cairo_new_path(self.context)
+}
+
+func (self *Context) MoveTo(x, y float64) {
+	defer runtime.KeepAlive(self)
+	C.cairo_move_to(self.context, C.double(x), C.double(y))
+}
+
+func (self *Context) NewSubPath() {
+	defer runtime.KeepAlive(self)
+	C.cairo_new_sub_path(self.context)
+}
+
+func (self *Context) LineTo(x, y float64) {
+	defer runtime.KeepAlive(self)
+	C.cairo_line_to(self.context, C.double(x), C.double(y))
+}
+
+func (self *Context) CurveTo(x1, y1, x2, y2, x3, y3 float64) {
+	defer runtime.KeepAlive(self)
+	C.cairo_curve_to(self.context,
+		C.double(x1), C.double(y1),
+		C.double(x2), C.double(y2),
+		C.double(x3), C.double(y3))
+}
+
+func (self *Context) Arc(xc, yc, radius, angle1, angle2 float64) {
+	defer runtime.KeepAlive(self)
+	C.cairo_arc(self.context,
+		C.double(xc), C.double(yc),
+		C.double(radius),
+		C.double(angle1), C.double(angle2))
+}
+
+func (self *Context) ArcNegative(xc, yc, radius, angle1, angle2 float64) {
+	defer runtime.KeepAlive(self)
+	C.cairo_arc_negative(self.context,
+		C.double(xc), C.double(yc),
+		C.double(radius),
+		C.double(angle1), C.double(angle2))
+}
+
+func (self *Context) RelMoveTo(dx, dy float64) {
+	defer runtime.KeepAlive(self)
+	C.cairo_rel_move_to(self.context, C.double(dx), C.double(dy))
+}
+
+func (self *Context) RelLineTo(dx, dy float64) {
+	defer runtime.KeepAlive(self)
+	C.cairo_rel_line_to(self.context, C.double(dx), C.double(dy))
+}
+
+func (self *Context) RelCurveTo(dx1, dy1, dx2, dy2, dx3, dy3 float64) {
+	defer runtime.KeepAlive(self)
+	C.cairo_rel_curve_to(self.context,
+		C.double(dx1), C.double(dy1),
+		C.double(dx2), C.double(dy2),
+		C.double(dx3), C.double(dy3))
+}
+
+func (self *Context) Rectangle(x, y, width, height float64) {
+	defer runtime.KeepAlive(self)
+	C.cairo_rectangle(self.context,
+		C.double(x), C.double(y),
+		C.double(width), C.double(height))
+}
+
+func (self *Context) ClosePath() {
+	defer runtime.KeepAlive(self)
+	C.cairo_close_path(self.context)
+}
+
+func (self *Context) PathExtents() (left, top, right, bottom float64) {
+	defer runtime.KeepAlive(self)
+	C.cairo_path_extents(self.context,
+		(*C.double)(&left), (*C.double)(&top),
+		(*C.double)(&right), (*C.double)(&bottom))
+	return left, top, right, bottom
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Painting methods
+//
+// The painting, clipping and text drawing methods return
+// the status of the context as error after the operation.
+// Painting methods that fail because the acquire callback of
+// a raster source pattern returned an error wrap that error.
+
+func (self *Context) Paint() error {
+	defer runtime.KeepAlive(self)
+	C.cairo_paint(self.context)
+	return self.drawError()
+}
+
+func (self *Context) PaintWithAlpha(alpha float64) error {
+	defer runtime.KeepAlive(self)
+	C.cairo_paint_with_alpha(self.context, C.double(alpha))
+	return self.drawError()
+}
+
+func (self *Context) Mask(pattern *Pattern) error {
+	defer runtime.KeepAlive(self)
+	defer runtime.KeepAlive(pattern)
+	C.cairo_mask(self.context, pattern.pattern)
+	return self.drawError(pattern.pattern)
+}
+
+func (self *Context) MaskSurface(surface *Surface, surface_x, surface_y float64) error {
+	defer runtime.KeepAlive(self)
+	defer runtime.KeepAlive(surface)
+	C.cairo_mask_surface(self.context, surface.surface, C.double(surface_x), C.double(surface_y))
+	return self.drawError()
+}
+
+func (self *Context) Stroke() error {
+	defer runtime.KeepAlive(self)
+	C.cairo_stroke(self.context)
+	return self.drawError()
+}
+
+func (self *Context) StrokePreserve() error {
+	defer runtime.KeepAlive(self)
+	C.cairo_stroke_preserve(self.context)
+	return self.drawError()
+}
+
+func (self *Context) Fill() error {
+	defer runtime.KeepAlive(self)
+	C.cairo_fill(self.context)
+	return self.drawError()
+}
+
+func (self *Context) FillPreserve() error {
+	defer runtime.KeepAlive(self)
+	C.cairo_fill_preserve(self.context)
+	return self.drawError()
+}
+
+func (self *Context) CopyPage() error {
+	defer runtime.KeepAlive(self)
+	C.cairo_copy_page(self.context)
+	return statusError(self.Status())
+}
+
+func (self *Context) ShowPage() error {
+	defer runtime.KeepAlive(self)
+	C.cairo_show_page(self.context)
+	return statusError(self.Status())
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Insideness testing
+
+func (self *Context) InStroke(x, y float64) bool {
+	defer runtime.KeepAlive(self)
+	return C.cairo_in_stroke(self.context, C.double(x), C.double(y)) != 0
+}
+
+func (self *Context) InFill(x, y float64) bool {
+	defer runtime.KeepAlive(self)
+	return C.cairo_in_fill(self.context, C.double(x), C.double(y)) != 0
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Rectangular extents
+
+func (self *Context) StrokeExtents() (left, top, right, bottom float64) {
+	defer runtime.KeepAlive(self)
+	C.cairo_stroke_extents(self.context,
+		(*C.double)(&left), (*C.double)(&top),
+		(*C.double)(&right), (*C.double)(&bottom))
+	return left, top, right, bottom
+}
+
+func (self *Context) FillExtents() (left, top, right, bottom float64) {
+	defer runtime.KeepAlive(self)
+	C.cairo_fill_extents(self.context,
+		(*C.double)(&left), (*C.double)(&top),
+		(*C.double)(&right), (*C.double)(&bottom))
+	return left, top, right, bottom
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Clipping methods
+
+func (self *Context) ResetClip() {
+	defer runtime.KeepAlive(self)
+	C.cairo_reset_clip(self.context)
+}
+
+func (self *Context) Clip() error {
+	defer runtime.KeepAlive(self)
+	C.cairo_clip(self.context)
+	return statusError(self.Status())
+}
+
+func (self *Context) ClipPreserve() error {
+	defer runtime.KeepAlive(self)
+	C.cairo_clip_preserve(self.context)
+	return statusError(self.Status())
+}
+
+func (self *Context) ClipExtents() (left, top, right, bottom float64) {
+	defer runtime.KeepAlive(self)
+	C.cairo_clip_extents(self.context,
+		(*C.double)(&left), (*C.double)(&top),
+		(*C.double)(&right), (*C.double)(&bottom))
+	return left, top, right, bottom
+}
+
+func (self *Context) ClipRectangleList() ([]Rectangle, Status) {
+	defer runtime.KeepAlive(self)
+	list := C.cairo_copy_clip_rectangle_list(self.context)
+	defer C.cairo_rectangle_list_destroy(list)
+	rects := make([]Rectangle, int(list.num_rectangles))
+	if len(rects) > 0 {
+		C.memcpy(unsafe.Pointer(&rects[0]), unsafe.Pointer(list.rectangles), C.size_t(len(rects))*C.sizeof_cairo_rectangle_t)
+	}
+	return rects, Status(list.status)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Font/Text methods
+
+func (self *Context) SelectFontFace(name string, slant FontSlant, weight FontWeight) {
+	defer runtime.KeepAlive(self)
+	s := C.CString(name)
+	C.cairo_select_font_face(self.context, s, C.cairo_font_slant_t(slant), C.cairo_font_weight_t(weight))
+	C.free(unsafe.Pointer(s))
+}
+
+func (self *Context) SetFontSize(size float64) {
+	defer runtime.KeepAlive(self)
+	C.cairo_set_font_size(self.context, C.double(size))
+}
+
+func (self *Context) SetFontMatrix(matrix Matrix) {
+	defer runtime.KeepAlive(self)
+	C.cairo_set_font_matrix(self.context, matrix.cairo_matrix_t())
+}
+
+// SetFontOptions sets the font options of the context,
+// they are merged with the font options of the target surface.
+func (self *Context) SetFontOptions(fontOptions *FontOptions) {
+	defer runtime.KeepAlive(self)
+	defer runtime.KeepAlive(fontOptions)
+	C.cairo_set_font_options(self.context, fontOptions.options)
+}
+
+// GetFontOptions returns a copy of the font options set on the context.
+func (self *Context) GetFontOptions() *FontOptions {
+	defer runtime.KeepAlive(self)
+	fontOptions := NewFontOptions()
+	C.cairo_get_font_options(self.context, fontOptions.options)
+	return fontOptions
+}
+
+func (self *Context) SetFontFace(fontFace *FontFace) {
+	defer runtime.KeepAlive(self)
+	defer runtime.KeepAlive(fontFace)
+	C.cairo_set_font_face(self.context, fontFace.face)
+}
+
+// GetFontFace returns the current font face of the context.
+func (self *Context) GetFontFace() *FontFace {
+	defer runtime.KeepAlive(self)
+	return newFontFace(C.cairo_font_face_reference(C.cairo_get_font_face(self.context)))
+}
+
+// SetScaledFont replaces the font face, font matrix and font options
+// of the context with those of scaledFont.
+func (self *Context) SetScaledFont(scaledFont *ScaledFont) {
+	defer runtime.KeepAlive(self)
+	defer runtime.KeepAlive(scaledFont)
+	C.cairo_set_scaled_font(self.context, scaledFont.scaledFont)
+}
+
+// GetScaledFont returns the scaled font for the current font face,
+// font matrix, CTM and font options of the context.
+func (self *Context) GetScaledFont() *ScaledFont {
+	defer runtime.KeepAlive(self)
+	return newScaledFont(C.cairo_scaled_font_reference(C.cairo_get_scaled_font(self.context)))
+}
+
+func (self *Context) ShowText(text string) error {
+	defer runtime.KeepAlive(self)
+	cs := C.CString(text)
+	C.cairo_show_text(self.context, cs)
+	C.free(unsafe.Pointer(cs))
+	return self.drawError()
+}
+
+// ShowGlyphs draws glyphs with the current font face, font size
+// and source. The glyph positions are in user-space.
+func (self *Context) ShowGlyphs(glyphs []Glyph) error {
+	defer runtime.KeepAlive(self)
+	cglyphs := cairoGlyphs(glyphs)
+	C.cairo_show_glyphs(self.context, firstGlyph(cglyphs), C.int(len(cglyphs)))
+	return self.drawError()
+}
+
+// ShowTextGlyphs draws glyphs like ShowGlyphs, and passes text
+// with clusters mapping its bytes to the glyphs to surfaces supporting it
+// (see Surface.HasShowTextGlyphs), so for example PDF text stays
+// selectable and searchable. With TEXT_CLUSTER_FLAG_BACKWARD the clusters
+// map the glyphs from end to start, like for right-to-left text.
+// Clusters not covering all of text and glyphs fail with STATUS_INVALID_CLUSTERS.
+func (self *Context) ShowTextGlyphs(text string, glyphs []Glyph, clusters []TextCluster, flags TextClusterFlag) error {
+	defer runtime.KeepAlive(self)
+	cs := C.CString(text)
+	defer C.free(unsafe.Pointer(cs))
+	cglyphs := cairoGlyphs(glyphs)
+	cclusters := cairoTextClusters(clusters)
+	var firstCluster *C.cairo_text_cluster_t
+	if len(cclusters) > 0 {
+		firstCluster = &cclusters[0]
+	}
+	C.cairo_show_text_glyphs(self.context, cs, C.int(len(text)),
+		firstGlyph(cglyphs), C.int(len(cglyphs)),
+		firstCluster, C.int(len(cclusters)),
+		C.cairo_text_cluster_flags_t(flags))
+	return self.drawError()
+}
+
+func (self *Context) TextPath(text string) error {
+	defer runtime.KeepAlive(self)
+	cs := C.CString(text)
+	C.cairo_text_path(self.context, cs)
+	C.free(unsafe.Pointer(cs))
+	return statusError(self.Status())
+}
+
+// GlyphPath adds the outlines of glyphs to the current path.
+func (self *Context) GlyphPath(glyphs []Glyph) error {
+	defer runtime.KeepAlive(self)
+	cglyphs := cairoGlyphs(glyphs)
+	C.cairo_glyph_path(self.context, firstGlyph(cglyphs), C.int(len(cglyphs)))
+	return statusError(self.Status())
+}
+
+func (self *Context) TextExtents(text string) *TextExtents {
+	defer runtime.KeepAlive(self)
+	cte := C.cairo_text_extents_t{}
+	cs := C.CString(text)
+	C.cairo_text_extents(self.context, cs, &cte)
+	C.free(unsafe.Pointer(cs))
+	return newTextExtents(&cte)
+}
+
+func (self *Context) GlyphExtents(glyphs []Glyph) *TextExtents {
+	defer runtime.KeepAlive(self)
+	var cte C.cairo_text_extents_t
+	cglyphs := cairoGlyphs(glyphs)
+	C.cairo_glyph_extents(self.context, firstGlyph(cglyphs), C.int(len(cglyphs)), &cte)
+	return newTextExtents(&cte)
+}
+
+func (self *Context) FontExtents() *FontExtents {
+	defer runtime.KeepAlive(self)
+	cfe := C.cairo_font_extents_t{}
+	C.cairo_font_extents(self.context, &cfe)
+	return newFontExtents(&cfe)
+}
@@ -0,0 +1,198 @@
+//go:build !goci
+// +build !goci
+
+package cairo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+
+	_ "golang.org/x/image/webp"
+)
+
+// MAX_IMAGE_SIZE is the maximum width and height of a cairo image surface.
+const MAX_IMAGE_SIZE = 32767
+
+// MaxDecodePixels limits the number of pixels (width * height)
+// of images decoded by NewSurfaceFromReader.
+var MaxDecodePixels = 64 * 1024 * 1024
+
+// NewSurfaceFromReader decodes an image in any format registered
+// with the image package (PNG, JPEG, GIF and WebP are registered by
+// this package) and returns it as new image surface.
+//
+// The orientation of JPEG images with EXIF data is applied.
+// The surface format is FORMAT_A8 for alpha-only images, FORMAT_RGB24
+// for opaque images and FORMAT_ARGB32 for everything else.
+// Images larger than MAX_IMAGE_SIZE in one dimension or with more than
+// MaxDecodePixels pixels are rejected before their pixels get decoded.
+func NewSurfaceFromReader(r io.Reader) (*Surface, error) {
+	// Keep the bytes consumed by DecodeConfig to replay them for Decode
+	var header bytes.Buffer
+	config, format, err := image.DecodeConfig(io.TeeReader(r, &header))
+	if err != nil {
+		return nil, err
+	}
+	if config.Width <= 0 || config.Height <= 0 ||
+		config.Width > MAX_IMAGE_SIZE || config.Height > MAX_IMAGE_SIZE ||
+		config.Width*config.Height > MaxDecodePixels {
+		return nil, fmt.Errorf("cairo.NewSurfaceFromReader(): invalid %s image size %dx%d", format, config.Width, config.Height)
+	}
+
+	orientation := 1
+	if format == "jpeg" {
+		orientation = exifOrientation(header.Bytes())
+	}
+
+	img, _, err := image.Decode(io.MultiReader(&header, r))
+	if err != nil {
+		return nil, err
+	}
+	if orientation > 1 {
+		img = newOrientedImage(img, orientation)
+	}
+
+	surface, err := NewSurfaceChecked(formatForImage(img), img.Bounds().Dx(), img.Bounds().Dy())
+	if err != nil {
+		return nil, err
+	}
+	if err := surface.SetImage(img); err != nil {
+		surface.Destroy()
+		return nil, err
+	}
+	return surface, nil
+}
+
+// formatForImage chooses the surface format for img by its color model.
+func formatForImage(img image.Image) Format {
+	switch model := img.ColorModel(); model {
+	case color.AlphaModel, color.Alpha16Model:
+		return FORMAT_A8
+	case color.GrayModel, color.Gray16Model, color.YCbCrModel, color.CMYKModel:
+		return FORMAT_RGB24
+	default:
+		if palette, ok := model.(color.Palette); ok {
+			for _, c := range palette {
+				if _, _, _, a := c.RGBA(); a != 0xffff {
+					return FORMAT_ARGB32
+				}
+			}
+			return FORMAT_RGB24
+		}
+	}
+	if o, ok := img.(interface{ Opaque() bool }); ok && o.Opaque() {
+		return FORMAT_RGB24
+	}
+	return FORMAT_ARGB32
+}
+
+// exifOrientation returns the EXIF orientation tag (1 to 8)
+// found in the APP1 segment of the JPEG data,
+// or 1 if there is none.
+func exifOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xff || data[1] != 0xd8 {
+		return 1
+	}
+	data = data[2:]
+	for len(data) >= 4 && data[0] == 0xff {
+		marker := data[1]
+		length := int(binary.BigEndian.Uint16(data[2:4]))
+		if marker == 0xda || length < 2 || len(data) < 2+length {
+			// Start of scan or truncated header
+			return 1
+		}
+		segment := data[4 : 2+length]
+		if marker == 0xe1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return tiffOrientation(segment[6:])
+		}
+		data = data[2+length:]
+	}
+	return 1
+}
+
+// tiffOrientation reads the orientation tag 0x0112
+// from IFD0 of the TIFF structure in EXIF data.
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+	ifd := int(order.Uint32(tiff[4:8]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < count; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		// Tag 0x0112 with type SHORT
+		if order.Uint16(tiff[entry:]) == 0x0112 && order.Uint16(tiff[entry+2:]) == 3 {
+			orientation := int(order.Uint16(tiff[entry+8:]))
+			if orientation < 1 || orientation > 8 {
+				return 1
+			}
+			return orientation
+		}
+	}
+	return 1
+}
+
+// orientedImage presents an image transformed
+// according to an EXIF orientation.
+type orientedImage struct {
+	image.Image
+	orientation int
+	bounds      image.Rectangle
+}
+
+func newOrientedImage(img image.Image, orientation int) *orientedImage {
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	if orientation >= 5 {
+		// Orientations 5 to 8 swap width and height
+		w, h = h, w
+	}
+	return &orientedImage{Image: img, orientation: orientation, bounds: image.Rect(0, 0, w, h)}
+}
+
+func (self *orientedImage) Bounds() image.Rectangle {
+	return self.bounds
+}
+
+func (self *orientedImage) At(x, y int) color.Color {
+	src := self.Image.Bounds()
+	w, h := src.Dx(), src.Dy()
+	switch self.orientation {
+	case 2: // mirrored horizontally
+		x = w - 1 - x
+	case 3: // rotated 180°
+		x, y = w-1-x, h-1-y
+	case 4: // mirrored vertically
+		y = h - 1 - y
+	case 5: // transposed
+		x, y = y, x
+	case 6: // rotated 90° clockwise
+		x, y = y, h-1-x
+	case 7: // transversed
+		x, y = w-1-y, h-1-x
+	case 8: // rotated 90° counter-clockwise
+		x, y = w-1-y, x
+	}
+	return self.Image.At(src.Min.X+x, src.Min.Y+y)
+}
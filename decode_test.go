@@ -0,0 +1,172 @@
+//go:build !goci
+// +build !goci
+
+package cairo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// exifTIFF returns TIFF data with IFD0 holding the orientation tag
+// of the given type and value.
+func exifTIFF(order binary.ByteOrder, tagType uint16, orientation uint16) []byte {
+	var tiff bytes.Buffer
+	if order == binary.LittleEndian {
+		tiff.WriteString("II")
+	} else {
+		tiff.WriteString("MM")
+	}
+	binary.Write(&tiff, order, uint16(42))
+	binary.Write(&tiff, order, uint32(8))
+	// IFD0 with an unrelated tag before the orientation
+	binary.Write(&tiff, order, uint16(2))
+	binary.Write(&tiff, order, []uint16{0x010f, 2})
+	binary.Write(&tiff, order, []uint32{4, 0})
+	binary.Write(&tiff, order, []uint16{0x0112, tagType})
+	binary.Write(&tiff, order, uint32(1))
+	binary.Write(&tiff, order, []uint16{orientation, 0})
+	binary.Write(&tiff, order, uint32(0))
+	return tiff.Bytes()
+}
+
+// jpegSegment returns a JPEG marker segment with payload.
+func jpegSegment(marker byte, payload []byte) []byte {
+	segment := []byte{0xff, marker, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+// jpegHeader returns the start of a JPEG file with the given segments.
+func jpegHeader(segments ...[]byte) []byte {
+	data := []byte{0xff, 0xd8}
+	for _, segment := range segments {
+		data = append(data, segment...)
+	}
+	return data
+}
+
+func exifSegment(tiff []byte) []byte {
+	return jpegSegment(0xe1, append([]byte("Exif\x00\x00"), tiff...))
+}
+
+func TestExifOrientation(t *testing.T) {
+	type test struct {
+		name string
+		data []byte
+		want int
+	}
+	var tests []test
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		for orientation := 1; orientation <= 8; orientation++ {
+			tests = append(tests, test{
+				name: order.String(),
+				data: jpegHeader(exifSegment(exifTIFF(order, 3, uint16(orientation)))),
+				want: orientation,
+			})
+		}
+		tests = append(tests,
+			test{"out of range " + order.String(), jpegHeader(exifSegment(exifTIFF(order, 3, 9))), 1},
+			test{"zero " + order.String(), jpegHeader(exifSegment(exifTIFF(order, 3, 0))), 1},
+			test{"type LONG " + order.String(), jpegHeader(exifSegment(exifTIFF(order, 4, 6))), 1},
+		)
+	}
+	valid := exifTIFF(binary.BigEndian, 3, 6)
+	badIFD := append([]byte(nil), valid...)
+	binary.BigEndian.PutUint32(badIFD[4:], 1000)
+	tests = append(tests,
+		test{"after APP0", jpegHeader(jpegSegment(0xe0, []byte("JFIF\x00\x01\x01")), exifSegment(valid)), 6},
+		test{"after start of scan", jpegHeader(jpegSegment(0xda, []byte{1, 2}), exifSegment(valid)), 1},
+		test{"APP1 without Exif", jpegHeader(jpegSegment(0xe1, append([]byte("http://ns.adobe.com/xap/1.0/\x00"), valid...))), 1},
+		test{"garbage TIFF", jpegHeader(exifSegment([]byte("XX\x00\x2a\x00\x00\x00\x08garbage"))), 1},
+		test{"IFD out of range", jpegHeader(exifSegment(badIFD)), 1},
+		test{"truncated IFD", jpegHeader(exifSegment(valid[:len(valid)-16])), 1},
+		test{"truncated TIFF header", jpegHeader(exifSegment(valid[:6])), 1},
+		test{"truncated segment", jpegHeader(exifSegment(valid))[:20], 1},
+		test{"invalid segment length", jpegHeader([]byte{0xff, 0xe1, 0x00, 0x01}), 1},
+		test{"no segments", jpegHeader(), 1},
+		test{"PNG", []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR"), 1},
+		test{"empty", nil, 1},
+	)
+	for _, test := range tests {
+		if got := exifOrientation(test.data); got != test.want {
+			t.Errorf("%s: exifOrientation() = %d, expected %d", test.name, got, test.want)
+		}
+	}
+}
+
+func TestOrientedImage(t *testing.T) {
+	// Source pixels a b c / d e f identified by their gray value
+	src := image.NewGray(image.Rect(5, 7, 8, 9))
+	for i, v := range []uint8{'a', 'b', 'c', 'd', 'e', 'f'} {
+		src.SetGray(5+i%3, 7+i/3, color.Gray{v})
+	}
+	tests := []struct {
+		orientation int
+		want        []string
+	}{
+		{1, []string{"abc", "def"}},
+		{2, []string{"cba", "fed"}},
+		{3, []string{"fed", "cba"}},
+		{4, []string{"def", "abc"}},
+		{5, []string{"ad", "be", "cf"}},
+		{6, []string{"da", "eb", "fc"}},
+		{7, []string{"fc", "eb", "da"}},
+		{8, []string{"cf", "be", "ad"}},
+	}
+	for _, test := range tests {
+		img := newOrientedImage(src, test.orientation)
+		bounds := img.Bounds()
+		if bounds != image.Rect(0, 0, len(test.want[0]), len(test.want)) {
+			t.Errorf("orientation %d: bounds %v", test.orientation, bounds)
+			continue
+		}
+		for y, row := range test.want {
+			got := make([]byte, len(row))
+			for x := range got {
+				got[x] = color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y
+			}
+			if string(got) != row {
+				t.Errorf("orientation %d: row %d is %q, expected %q", test.orientation, y, got, row)
+			}
+		}
+	}
+}
+
+func TestNewSurfaceFromReaderOrientation(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 32, 16))
+	var jpegData bytes.Buffer
+	if err := jpeg.Encode(&jpegData, src, nil); err != nil {
+		t.Fatal(err)
+	}
+	// Insert the EXIF segment after the start of image marker
+	data := jpegHeader(exifSegment(exifTIFF(binary.LittleEndian, 3, 6)))
+	data = append(data, jpegData.Bytes()[2:]...)
+
+	surface, err := NewSurfaceFromReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer surface.Destroy()
+	if surface.GetWidth() != 16 || surface.GetHeight() != 32 || surface.GetFormat() != FORMAT_RGB24 {
+		t.Errorf("rotated JPEG surface is %dx%d format %v, expected 16x32 FORMAT_RGB24",
+			surface.GetWidth(), surface.GetHeight(), surface.GetFormat())
+	}
+
+	// EXIF orientation is only applied to JPEG
+	var pngData bytes.Buffer
+	png.Encode(&pngData, src)
+	surface, err = NewSurfaceFromReader(&pngData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer surface.Destroy()
+	if surface.GetWidth() != 32 || surface.GetHeight() != 16 {
+		t.Errorf("PNG surface is %dx%d, expected 32x16", surface.GetWidth(), surface.GetHeight())
+	}
+}
@@ -0,0 +1,219 @@
+//go:build !goci
+// +build !goci
+
+package cairo
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"io"
+	"strconv"
+
+	"github.com/ungerik/go-cairo/extimage"
+	"golang.org/x/image/bmp"
+	"golang.org/x/image/tiff"
+)
+
+// ImageFormat is an output format for Surface.Encode.
+type ImageFormat int
+
+const (
+	IMAGE_FORMAT_PNG ImageFormat = iota
+	IMAGE_FORMAT_JPEG
+	IMAGE_FORMAT_GIF
+	IMAGE_FORMAT_BMP
+	IMAGE_FORMAT_TIFF
+	IMAGE_FORMAT_PPM
+	IMAGE_FORMAT_PAM
+)
+
+func (self ImageFormat) String() string {
+	switch self {
+	case IMAGE_FORMAT_PNG:
+		return "PNG"
+	case IMAGE_FORMAT_JPEG:
+		return "JPEG"
+	case IMAGE_FORMAT_GIF:
+		return "GIF"
+	case IMAGE_FORMAT_BMP:
+		return "BMP"
+	case IMAGE_FORMAT_TIFF:
+		return "TIFF"
+	case IMAGE_FORMAT_PPM:
+		return "PPM"
+	case IMAGE_FORMAT_PAM:
+		return "PAM"
+	}
+	return "ImageFormat(" + strconv.Itoa(int(self)) + ")"
+}
+
+// EncodeOptions are passed to the Go encoders used by Surface.Encode.
+// Nil fields select the defaults of the respective encoder.
+type EncodeOptions struct {
+	// JPEG sets the quality of IMAGE_FORMAT_JPEG.
+	JPEG *jpeg.Options
+	// GIF sets the number of colors, the palette quantizer
+	// and the dithering drawer of IMAGE_FORMAT_GIF.
+	// Without a quantizer the Plan 9 palette with
+	// Floyd-Steinberg dithering is used.
+	GIF *gif.Options
+	// TIFF sets the compression of IMAGE_FORMAT_TIFF.
+	TIFF *tiff.Options
+}
+
+// Encode writes the image surface to w in the given format.
+//
+// FORMAT_ARGB32 surfaces are un-premultiplied, so formats with an
+// alpha channel (PNG, GIF, BMP, TIFF, PAM) get straight alpha and
+// formats without one (JPEG, PPM) get the un-premultiplied colors.
+// FORMAT_RGB24 surfaces are encoded as opaque RGB.
+// FORMAT_A8 surfaces are encoded as grayscale images of their alpha values.
+// IMAGE_FORMAT_PNG uses cairo's own PNG writer and ignores options.
+func (self *Surface) Encode(w io.Writer, format ImageFormat, options *EncodeOptions) error {
+	if format == IMAGE_FORMAT_PNG {
+		return self.WritePNG(w)
+	}
+	if options == nil {
+		options = &EncodeOptions{}
+	}
+	img, err := self.encodableImage()
+	if err != nil {
+		return err
+	}
+	switch format {
+	case IMAGE_FORMAT_JPEG:
+		return jpeg.Encode(w, img, options.JPEG)
+	case IMAGE_FORMAT_GIF:
+		return gif.Encode(w, img, options.GIF)
+	case IMAGE_FORMAT_BMP:
+		return bmp.Encode(w, img)
+	case IMAGE_FORMAT_TIFF:
+		return tiff.Encode(w, img, options.TIFF)
+	case IMAGE_FORMAT_PPM:
+		return encodePPM(w, img)
+	case IMAGE_FORMAT_PAM:
+		return encodePAM(w, img)
+	}
+	return fmt.Errorf("cairo.Surface.Encode(): unsupported image format %s", format)
+}
+
+// encodableImage returns a copy of the surface pixels as one of the
+// standard image types understood by the Go encoders:
+// *image.NRGBA for FORMAT_ARGB32, *image.RGBA for FORMAT_RGB24
+// and *image.Gray for FORMAT_A8.
+func (self *Surface) encodableImage() (image.Image, error) {
+	img, err := self.Image()
+	if err != nil {
+		return nil, err
+	}
+	switch src := img.(type) {
+	case *extimage.BGRA:
+		dst := image.NewNRGBA(src.Rect)
+		for y := src.Rect.Min.Y; y < src.Rect.Max.Y; y++ {
+			for x := src.Rect.Min.X; x < src.Rect.Max.X; x++ {
+				c := src.BGRAAt(x, y)
+				i := dst.PixOffset(x, y)
+				dst.Pix[i+0] = unpremultiply(c.R, c.A)
+				dst.Pix[i+1] = unpremultiply(c.G, c.A)
+				dst.Pix[i+2] = unpremultiply(c.B, c.A)
+				dst.Pix[i+3] = c.A
+			}
+		}
+		return dst, nil
+
+	case *extimage.BGRN:
+		dst := image.NewRGBA(src.Rect)
+		for y := src.Rect.Min.Y; y < src.Rect.Max.Y; y++ {
+			for x := src.Rect.Min.X; x < src.Rect.Max.X; x++ {
+				c := src.BGRNAt(x, y)
+				i := dst.PixOffset(x, y)
+				dst.Pix[i+0] = c.R
+				dst.Pix[i+1] = c.G
+				dst.Pix[i+2] = c.B
+				dst.Pix[i+3] = 0xff
+			}
+		}
+		return dst, nil
+
+	default:
+		// Image returns a copy of the data that can be reused
+		alpha := src.(*image.Alpha)
+		return &image.Gray{Pix: alpha.Pix, Stride: alpha.Stride, Rect: alpha.Rect}, nil
+	}
+}
+
+// unpremultiply reverses cairo's alpha premultiplication of c with rounding.
+func unpremultiply(c, a uint8) uint8 {
+	if a == 0 {
+		return 0
+	}
+	return uint8((uint32(c)*0xff + uint32(a)/2) / uint32(a))
+}
+
+// encodePPM writes img as binary PPM (P6), or PGM (P5) for *image.Gray.
+// Alpha channels are dropped.
+func encodePPM(w io.Writer, img image.Image) error {
+	bw := bufio.NewWriter(w)
+	rect := img.Bounds()
+	switch img := img.(type) {
+	case *image.Gray:
+		fmt.Fprintf(bw, "P5\n%d %d\n255\n", rect.Dx(), rect.Dy())
+		for y := rect.Min.Y; y < rect.Max.Y; y++ {
+			i := img.PixOffset(rect.Min.X, y)
+			bw.Write(img.Pix[i : i+rect.Dx()])
+		}
+	default:
+		fmt.Fprintf(bw, "P6\n%d %d\n255\n", rect.Dx(), rect.Dy())
+		writeRGBRows(bw, img, false)
+	}
+	return bw.Flush()
+}
+
+// encodePAM writes img as PAM (P7) with the tuple type
+// GRAYSCALE, RGB or RGB_ALPHA (straight alpha).
+func encodePAM(w io.Writer, img image.Image) error {
+	bw := bufio.NewWriter(w)
+	rect := img.Bounds()
+	switch img := img.(type) {
+	case *image.Gray:
+		fmt.Fprintf(bw, "P7\nWIDTH %d\nHEIGHT %d\nDEPTH 1\nMAXVAL 255\nTUPLTYPE GRAYSCALE\nENDHDR\n", rect.Dx(), rect.Dy())
+		for y := rect.Min.Y; y < rect.Max.Y; y++ {
+			i := img.PixOffset(rect.Min.X, y)
+			bw.Write(img.Pix[i : i+rect.Dx()])
+		}
+	case *image.NRGBA:
+		fmt.Fprintf(bw, "P7\nWIDTH %d\nHEIGHT %d\nDEPTH 4\nMAXVAL 255\nTUPLTYPE RGB_ALPHA\nENDHDR\n", rect.Dx(), rect.Dy())
+		writeRGBRows(bw, img, true)
+	default:
+		fmt.Fprintf(bw, "P7\nWIDTH %d\nHEIGHT %d\nDEPTH 3\nMAXVAL 255\nTUPLTYPE RGB\nENDHDR\n", rect.Dx(), rect.Dy())
+		writeRGBRows(bw, img, false)
+	}
+	return bw.Flush()
+}
+
+// writeRGBRows writes the RGB or RGBA samples of an
+// *image.NRGBA or *image.RGBA row by row to bw.
+func writeRGBRows(bw *bufio.Writer, img image.Image, alpha bool) {
+	var pix []uint8
+	var stride int
+	switch img := img.(type) {
+	case *image.NRGBA:
+		pix, stride = img.Pix, img.Stride
+	case *image.RGBA:
+		pix, stride = img.Pix, img.Stride
+	}
+	rect := img.Bounds()
+	for y := 0; y < rect.Dy(); y++ {
+		row := pix[y*stride : y*stride+rect.Dx()*4]
+		for i := 0; i < len(row); i += 4 {
+			if alpha {
+				bw.Write(row[i : i+4])
+			} else {
+				bw.Write(row[i : i+3])
+			}
+		}
+	}
+}
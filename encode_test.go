@@ -0,0 +1,195 @@
+//go:build !goci
+// +build !goci
+
+package cairo
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"strings"
+	"testing"
+)
+
+// decodePNM decodes the binary PPM, PGM and PAM images written by Encode.
+func decodePNM(r io.Reader) (image.Image, string, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.ReadString('\n')
+	if err != nil {
+		return nil, "", err
+	}
+	var width, height, depth int
+	tupleType := ""
+	switch strings.TrimSpace(magic) {
+	case "P5", "P6":
+		depth = 1
+		tupleType = "GRAYSCALE"
+		if magic == "P6\n" {
+			depth = 3
+			tupleType = "RGB"
+		}
+		var maxVal int
+		if _, err := fmt.Fscanf(br, "%d %d\n%d\n", &width, &height, &maxVal); err != nil {
+			return nil, "", err
+		}
+	case "P7":
+		for {
+			line, err := br.ReadString('\n')
+			if err != nil {
+				return nil, "", err
+			}
+			fields := strings.Fields(line)
+			if len(fields) == 1 && fields[0] == "ENDHDR" {
+				break
+			}
+			if len(fields) != 2 {
+				return nil, "", fmt.Errorf("invalid PAM header line %q", line)
+			}
+			switch fields[0] {
+			case "WIDTH":
+				fmt.Sscan(fields[1], &width)
+			case "HEIGHT":
+				fmt.Sscan(fields[1], &height)
+			case "DEPTH":
+				fmt.Sscan(fields[1], &depth)
+			case "TUPLTYPE":
+				tupleType = fields[1]
+			}
+		}
+	default:
+		return nil, "", fmt.Errorf("invalid magic %q", magic)
+	}
+	pix := make([]byte, width*height*depth)
+	if _, err := io.ReadFull(br, pix); err != nil {
+		return nil, "", err
+	}
+	if _, err := br.ReadByte(); err != io.EOF {
+		return nil, "", fmt.Errorf("trailing data after %d samples", len(pix))
+	}
+	if depth == 1 {
+		return &image.Gray{Pix: pix, Stride: width, Rect: image.Rect(0, 0, width, height)}, tupleType, nil
+	}
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for i := 0; i < width*height; i++ {
+		copy(img.Pix[i*4:], pix[i*depth:i*depth+3])
+		img.Pix[i*4+3] = 0xff
+		if depth == 4 {
+			img.Pix[i*4+3] = pix[i*depth+3]
+		}
+	}
+	return img, tupleType, nil
+}
+
+// newTestSurface returns a surface of format with the
+// raw pixel data of rows, one slice per row.
+func newTestSurface(t *testing.T, format Format, width int, rows ...[]byte) *Surface {
+	t.Helper()
+	surface, err := NewSurfaceChecked(format, width, len(rows))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := make([]byte, surface.GetStride()*len(rows))
+	for y, row := range rows {
+		copy(data[y*surface.GetStride():], row)
+	}
+	if err := surface.SetData(data); err != nil {
+		t.Fatal(err)
+	}
+	return surface
+}
+
+func encodeDecode(t *testing.T, surface *Surface, format ImageFormat) (image.Image, string) {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := surface.Encode(&buf, format, nil); err != nil {
+		t.Fatalf("Encode(%s): %v", format, err)
+	}
+	img, tupleType, err := decodePNM(&buf)
+	if err != nil {
+		t.Fatalf("decoding %s: %v", format, err)
+	}
+	return img, tupleType
+}
+
+func TestEncodePNMRoundTrip(t *testing.T) {
+	// Premultiplied BGRA in little-endian byte order
+	argb := newTestSurface(t, FORMAT_ARGB32, 3,
+		[]byte{0, 0, 255, 255, 0, 32, 0, 128, 0, 0, 0, 0},
+		[]byte{10, 20, 30, 255, 0x40, 0x40, 0x40, 0x40, 1, 1, 1, 1},
+	)
+	defer argb.Destroy()
+	// Straight RGBA of the surface pixels
+	want := []color.NRGBA{
+		{255, 0, 0, 255}, {0, 64, 0, 128}, {0, 0, 0, 0},
+		{30, 20, 10, 255}, {255, 255, 255, 0x40}, {255, 255, 255, 1},
+	}
+
+	img, tupleType := encodeDecode(t, argb, IMAGE_FORMAT_PAM)
+	if tupleType != "RGB_ALPHA" {
+		t.Errorf("PAM tuple type %s, expected RGB_ALPHA", tupleType)
+	}
+	for i, c := range want {
+		if got := img.(*image.NRGBA).NRGBAAt(i%3, i/3); got != c {
+			t.Errorf("PAM pixel %d: got %v, expected %v", i, got, c)
+		}
+	}
+
+	img, tupleType = encodeDecode(t, argb, IMAGE_FORMAT_PPM)
+	if tupleType != "RGB" {
+		t.Errorf("PPM type %s, expected RGB", tupleType)
+	}
+	for i, c := range want {
+		c.A = 0xff
+		if got := img.(*image.NRGBA).NRGBAAt(i%3, i/3); got != c {
+			t.Errorf("PPM pixel %d: got %v, expected %v", i, got, c)
+		}
+	}
+
+	rgb := newTestSurface(t, FORMAT_RGB24, 2, []byte{1, 2, 3, 0xaa, 4, 5, 6, 0xbb})
+	defer rgb.Destroy()
+	img, tupleType = encodeDecode(t, rgb, IMAGE_FORMAT_PAM)
+	if tupleType != "RGB" {
+		t.Errorf("PAM tuple type %s, expected RGB", tupleType)
+	}
+	for i, c := range []color.NRGBA{{3, 2, 1, 255}, {6, 5, 4, 255}} {
+		if got := img.(*image.NRGBA).NRGBAAt(i, 0); got != c {
+			t.Errorf("RGB24 PAM pixel %d: got %v, expected %v", i, got, c)
+		}
+	}
+
+	a8 := newTestSurface(t, FORMAT_A8, 3, []byte{0, 0x80, 0xff}, []byte{1, 2, 3})
+	defer a8.Destroy()
+	for _, format := range []ImageFormat{IMAGE_FORMAT_PPM, IMAGE_FORMAT_PAM} {
+		img, tupleType = encodeDecode(t, a8, format)
+		if tupleType != "GRAYSCALE" {
+			t.Errorf("%s type %s, expected GRAYSCALE", format, tupleType)
+		}
+		gray := img.(*image.Gray)
+		if !bytes.Equal(gray.Pix, []byte{0, 0x80, 0xff, 1, 2, 3}) {
+			t.Errorf("%s gray pixels %v", format, gray.Pix)
+		}
+	}
+}
+
+func TestUnpremultiply(t *testing.T) {
+	tests := []struct {
+		c, a, want uint8
+	}{
+		{0, 0, 0},
+		{0x80, 0x80, 0xff},
+		{0x40, 0x80, 0x80},
+		{0x20, 0x80, 0x40},
+		{0x33, 0x66, 0x80},
+		{0xff, 0xff, 0xff},
+		{0x64, 0xff, 0x64},
+		{1, 2, 0x80},
+	}
+	for _, test := range tests {
+		if got := unpremultiply(test.c, test.a); got != test.want {
+			t.Errorf("unpremultiply(%#x, %#x) = %#x, expected %#x", test.c, test.a, got, test.want)
+		}
+	}
+}
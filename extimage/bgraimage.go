@@ -34,6 +34,12 @@ func (self *BGRA) Bounds() image.Rectangle {
 }
 
 func (self *BGRA) At(x, y int) color.Color {
+	return self.BGRAAt(x, y)
+}
+
+// BGRAAt returns the alpha-premultiplied color of the pixel at (x, y)
+// without boxing it into a color.Color interface.
+func (self *BGRA) BGRAAt(x, y int) BGRAColor {
 	if !(image.Point{x, y}.In(self.Rect)) {
 		return BGRAColor{}
 	}
@@ -34,6 +34,12 @@ func (self *BGRN) Bounds() image.Rectangle {
 }
 
 func (self *BGRN) At(x, y int) color.Color {
+	return self.BGRNAt(x, y)
+}
+
+// BGRNAt returns the color of the pixel at (x, y)
+// without boxing it into a color.Color interface.
+func (self *BGRN) BGRNAt(x, y int) BGRNColor {
 	if !(image.Point{x, y}.In(self.Rect)) {
 		return BGRNColor{}
 	}
@@ -48,7 +54,7 @@ func (self *BGRN) At(x, y int) color.Color {
 // PixOffset returns the index of the first element of Pix that corresponds to
 // the pixel at (x, y).
 func (self *BGRN) PixOffset(x, y int) int {
-	return (y-self.Rect.Min.Y)*self.Stride + (x-self.Rect.Min.X)*3
+	return (y-self.Rect.Min.Y)*self.Stride + (x-self.Rect.Min.X)*4
 }
 
 func (self *BGRN) Set(x, y int, c color.Color) {
@@ -0,0 +1,38 @@
+package extimage
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestBGRNPixOffset(t *testing.T) {
+	img := NewBGRN(image.Rect(10, 20, 13, 22))
+	if img.Stride != 12 || len(img.Pix) != 24 {
+		t.Fatalf("NewBGRN stride %d and %d bytes, expected 12 and 24", img.Stride, len(img.Pix))
+	}
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+		for x := img.Rect.Min.X; x < img.Rect.Max.X; x++ {
+			want := (y-20)*12 + (x-10)*4
+			if got := img.PixOffset(x, y); got != want {
+				t.Errorf("PixOffset(%d, %d) = %d, expected %d", x, y, got, want)
+			}
+		}
+	}
+
+	// Every pixel keeps its own color, so neighbouring pixels don't overlap
+	colors := make(map[image.Point]color.RGBA)
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+		for x := img.Rect.Min.X; x < img.Rect.Max.X; x++ {
+			c := color.RGBA{uint8(x), uint8(y), uint8(x + y), 0xff}
+			colors[image.Point{x, y}] = c
+			img.Set(x, y, c)
+		}
+	}
+	for p, c := range colors {
+		want := BGRNColor{R: c.R, G: c.G, B: c.B}
+		if got := img.BGRNAt(p.X, p.Y); got != want {
+			t.Errorf("BGRNAt(%d, %d) = %v, expected %v", p.X, p.Y, got, want)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+//go:build !goci
+// +build !goci
+
+package cairo
+
+// #include <cairo/cairo.h>
+// #include <stdlib.h>
+import "C"
+
+import (
+	"runtime"
+	"unsafe"
+)
+
+// newFontFace wraps f and destroys it when the FontFace
+// gets garbage collected without being destroyed.
+func newFontFace(f *C.cairo_font_face_t) *FontFace {
+	fontFace := &FontFace{face: f}
+	runtime.SetFinalizer(fontFace, (*FontFace).Destroy)
+	return fontFace
+}
+
+// NewToyFontFace creates a font face from a CSS2 like family name
+// ("serif", "sans-serif", "cursive", "fantasy", "monospace" or a font name),
+// slant and weight, like SelectFontFace does.
+func NewToyFontFace(family string, slant FontSlant, weight FontWeight) *FontFace {
+	cs := C.CString(family)
+	defer C.free(unsafe.Pointer(cs))
+	return newFontFace(C.cairo_toy_font_face_create(cs, C.cairo_font_slant_t(slant), C.cairo_font_weight_t(weight)))
+}
+
+// Reference returns a new FontFace for the same cairo_font_face_t
+// and increases its reference count.
+// Both FontFaces have to be destroyed.
+func (self *FontFace) Reference() *FontFace {
+	defer runtime.KeepAlive(self)
+	return newFontFace(C.cairo_font_face_reference(self.face))
+}
+
+func (self *FontFace) GetReferenceCount() int {
+	defer runtime.KeepAlive(self)
+	return int(C.cairo_font_face_get_reference_count(self.face))
+}
+
+func (self *FontFace) Status() Status {
+	defer runtime.KeepAlive(self)
+	return Status(C.cairo_font_face_status(self.face))
+}
+
+// Destroy decreases the reference count of the font face.
+// It is safe to call Destroy more than once, after the first call
+// all methods are no-ops and Status returns STATUS_NULL_POINTER.
+func (self *FontFace) Destroy() {
+	if self.face != nilFontFace {
+		C.cairo_font_face_destroy(self.face)
+		self.face = nilFontFace
+	}
+	runtime.SetFinalizer(self, nil)
+}
+
+// GetType returns the font backend of the font face.
+func (self *FontFace) GetType() FontType {
+	defer runtime.KeepAlive(self)
+	return FontType(C.cairo_font_face_get_type(self.face))
+}
+
+// GetFamily returns the family name of a FONT_TYPE_TOY font face,
+// or an empty string for other font faces.
+func (self *FontFace) GetFamily() string {
+	defer runtime.KeepAlive(self)
+	if self.GetType() != FONT_TYPE_TOY {
+		return ""
+	}
+	return C.GoString(C.cairo_toy_font_face_get_family(self.face))
+}
+
+// GetSlant returns the slant of a FONT_TYPE_TOY font face,
+// or FONT_SLANT_NORMAL for other font faces.
+func (self *FontFace) GetSlant() FontSlant {
+	defer runtime.KeepAlive(self)
+	if self.GetType() != FONT_TYPE_TOY {
+		return FONT_SLANT_NORMAL
+	}
+	return FontSlant(C.cairo_toy_font_face_get_slant(self.face))
+}
+
+// GetWeight returns the weight of a FONT_TYPE_TOY font face,
+// or FONT_WEIGHT_NORMAL for other font faces.
+func (self *FontFace) GetWeight() FontWeight {
+	defer runtime.KeepAlive(self)
+	if self.GetType() != FONT_TYPE_TOY {
+		return FONT_WEIGHT_NORMAL
+	}
+	return FontWeight(C.cairo_toy_font_face_get_weight(self.face))
+}
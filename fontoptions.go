@@ -0,0 +1,141 @@
+//go:build !goci
+// +build !goci
+
+package cairo
+
+/*
+#include <cairo/cairo.h>
+#include <cairo/cairo-version.h>
+#include <stdlib.h>
+
+#if CAIRO_VERSION < CAIRO_VERSION_ENCODE(1, 16, 0)
+static const char *cairo_font_options_get_variations(cairo_font_options_t *options) {
+	return NULL;
+}
+static void cairo_font_options_set_variations(cairo_font_options_t *options, const char *variations) {
+}
+#endif
+*/
+import "C"
+
+import (
+	"runtime"
+	"unsafe"
+)
+
+// newFontOptions wraps o and destroys it when the FontOptions
+// get garbage collected without being destroyed.
+func newFontOptions(o *C.cairo_font_options_t) *FontOptions {
+	fontOptions := &FontOptions{options: o}
+	runtime.SetFinalizer(fontOptions, (*FontOptions).Destroy)
+	return fontOptions
+}
+
+// NewFontOptions creates font options with all options set to default.
+func NewFontOptions() *FontOptions {
+	return newFontOptions(C.cairo_font_options_create())
+}
+
+func (self *FontOptions) Copy() *FontOptions {
+	defer runtime.KeepAlive(self)
+	return newFontOptions(C.cairo_font_options_copy(self.options))
+}
+
+// Destroy frees the font options.
+// It is safe to call Destroy more than once, after the first call
+// all methods are no-ops and Status returns STATUS_NULL_POINTER.
+func (self *FontOptions) Destroy() {
+	if self.options != nil {
+		C.cairo_font_options_destroy(self.options)
+		self.options = nil
+	}
+	runtime.SetFinalizer(self, nil)
+}
+
+func (self *FontOptions) Status() Status {
+	defer runtime.KeepAlive(self)
+	return Status(C.cairo_font_options_status(self.options))
+}
+
+// Merge sets all options of other that are not set to default.
+func (self *FontOptions) Merge(other *FontOptions) {
+	defer runtime.KeepAlive(self)
+	defer runtime.KeepAlive(other)
+	C.cairo_font_options_merge(self.options, other.options)
+}
+
+func (self *FontOptions) Equal(other *FontOptions) bool {
+	defer runtime.KeepAlive(self)
+	defer runtime.KeepAlive(other)
+	return C.cairo_font_options_equal(self.options, other.options) != 0
+}
+
+// Hash returns a hash of the font options
+// that is equal for font options that are Equal.
+func (self *FontOptions) Hash() uint64 {
+	defer runtime.KeepAlive(self)
+	return uint64(C.cairo_font_options_hash(self.options))
+}
+
+func (self *FontOptions) SetAntialias(antialias Antialias) {
+	defer runtime.KeepAlive(self)
+	C.cairo_font_options_set_antialias(self.options, C.cairo_antialias_t(antialias))
+}
+
+func (self *FontOptions) GetAntialias() Antialias {
+	defer runtime.KeepAlive(self)
+	return Antialias(C.cairo_font_options_get_antialias(self.options))
+}
+
+func (self *FontOptions) SetSubpixelOrder(subpixelOrder SubpixelOrder) {
+	defer runtime.KeepAlive(self)
+	C.cairo_font_options_set_subpixel_order(self.options, C.cairo_subpixel_order_t(subpixelOrder))
+}
+
+func (self *FontOptions) GetSubpixelOrder() SubpixelOrder {
+	defer runtime.KeepAlive(self)
+	return SubpixelOrder(C.cairo_font_options_get_subpixel_order(self.options))
+}
+
+func (self *FontOptions) SetHintStyle(hintStyle HintStyle) {
+	defer runtime.KeepAlive(self)
+	C.cairo_font_options_set_hint_style(self.options, C.cairo_hint_style_t(hintStyle))
+}
+
+func (self *FontOptions) GetHintStyle() HintStyle {
+	defer runtime.KeepAlive(self)
+	return HintStyle(C.cairo_font_options_get_hint_style(self.options))
+}
+
+func (self *FontOptions) SetHintMetrics(hintMetrics HintMetrics) {
+	defer runtime.KeepAlive(self)
+	C.cairo_font_options_set_hint_metrics(self.options, C.cairo_hint_metrics_t(hintMetrics))
+}
+
+func (self *FontOptions) GetHintMetrics() HintMetrics {
+	defer runtime.KeepAlive(self)
+	return HintMetrics(C.cairo_font_options_get_hint_metrics(self.options))
+}
+
+// SetVariations sets the OpenType font variations like "wght=700,wdth=80".
+// Use of this function has no effect with Cairo older than version 1.16
+func (self *FontOptions) SetVariations(variations string) {
+	defer runtime.KeepAlive(self)
+	if self.options == nil {
+		return
+	}
+	var cs *C.char
+	if variations != "" {
+		cs = C.CString(variations)
+		defer C.free(unsafe.Pointer(cs))
+	}
+	C.cairo_font_options_set_variations(self.options, cs)
+}
+
+func (self *FontOptions) GetVariations() string {
+	defer runtime.KeepAlive(self)
+	if self.options == nil {
+		return ""
+	}
+	return C.GoString(C.cairo_font_options_get_variations(self.options))
+}
@@ -4,44 +4,260 @@
 package cairo
 
 /*
+#include <stdint.h>
+#include <stdlib.h>
 #include <cairo/cairo.h>
 #include <cairo/cairo-ft.h>
+
+extern void goCairoFtFaceDone(uintptr_t face);
+
+static cairo_user_data_key_t go_cairo_ft_face_key;
+
+static void go_cairo_ft_face_done(void *face) {
+	goCairoFtFaceDone((uintptr_t)face);
+}
+
+// go_cairo_ft_font_face_attach ties the lifetime of the FreeType face
+// to the cairo font face, the FreeType face gets released
+// after cairo has destroyed the font face and all scaled fonts using it.
+static cairo_status_t go_cairo_ft_font_face_attach(cairo_font_face_t *font_face, uintptr_t face) {
+	return cairo_font_face_set_user_data(font_face, &go_cairo_ft_face_key, (void *)face, go_cairo_ft_face_done);
+}
+
+// go_cairo_ft_font_face_data returns the face attached to font_face
+// by go_cairo_ft_font_face_attach, or 0 if there is none.
+static uintptr_t go_cairo_ft_font_face_data(cairo_font_face_t *font_face) {
+	return (uintptr_t)cairo_font_face_get_user_data(font_face, &go_cairo_ft_face_key);
+}
 */
 import "C"
 
 import (
 	"fmt"
+	"runtime"
+	"runtime/cgo"
+	"sync"
 	"unsafe"
 )
 
-type Cairo_freetype struct {
+// ftLibrary is a reference counted FreeType library.
+// FreeType libraries are not thread safe, so creating
+// and releasing faces is serialized with mutex.
+type ftLibrary struct {
+	mutex   sync.Mutex
 	library C.FT_Library
+	refs    int
+}
+
+// sharedFtLibrary is used by all Cairo_freetype values and FreeType faces.
+// It is freed after DoneFreeType has been called for every InitFreeType
+// and all faces created with it have been destroyed.
+var sharedFtLibrary ftLibrary
+
+func (self *ftLibrary) acquire() (C.FT_Library, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	if self.refs == 0 {
+		if err := C.FT_Init_FreeType(&self.library); err != 0 {
+			self.library = nil
+			return nil, fmt.Errorf("FT_Init_FreeType error %v", err)
+		}
+	}
+	self.refs++
+	return self.library, nil
+}
+
+func (self *ftLibrary) release() error {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	if self.refs <= 0 {
+		return fmt.Errorf("FreeType library released more often than acquired")
+	}
+	self.refs--
+	if self.refs > 0 {
+		return nil
+	}
+	err := C.FT_Done_FreeType(self.library)
+	self.library = nil
+	if err != 0 {
+		return fmt.Errorf("FT_Done_FreeType error %v", err)
+	}
+	return nil
 }
 
+// ftFace is the FreeType face of a cairo font face.
+// It holds a reference of the library the face was created with
+// and the C memory of faces created from memory.
+type ftFace struct {
+	face    C.FT_Face
+	library *ftLibrary
+	data    unsafe.Pointer
+}
+
+// done releases the face, its data and its reference to the library,
+// it is called when cairo destroys the font face.
+func (self *ftFace) done() {
+	self.library.mutex.Lock()
+	C.FT_Done_Face(self.face)
+	self.library.mutex.Unlock()
+	C.free(self.data)
+	self.data = nil
+	self.library.release()
+}
+
+type Cairo_freetype struct {
+	handle *ftHandle
+}
+
+// ftHandle is the reference to sharedFtLibrary taken by InitFreeType.
+// Copies of a Cairo_freetype share it, so the reference
+// is released only once, by the first DoneFreeType.
+type ftHandle struct {
+	mutex    sync.Mutex
+	released bool
+}
+
+func (self *ftHandle) release() error {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	if self.released {
+		return nil
+	}
+	self.released = true
+	return sharedFtLibrary.release()
+}
+
+// initialized returns if ft was returned by InitFreeType
+// and DoneFreeType has not been called for it or one of its copies.
+func (ft Cairo_freetype) initialized() bool {
+	if ft.handle == nil {
+		return false
+	}
+	ft.handle.mutex.Lock()
+	defer ft.handle.mutex.Unlock()
+	return !ft.handle.released
+}
+
+// FT_LOAD_* flags for loading glyphs of a FreeType face
+type FtLoadFlags int
+
+const (
+	FT_LOAD_DEFAULT                     FtLoadFlags = C.FT_LOAD_DEFAULT
+	FT_LOAD_NO_HINTING                  FtLoadFlags = C.FT_LOAD_NO_HINTING
+	FT_LOAD_NO_BITMAP                   FtLoadFlags = C.FT_LOAD_NO_BITMAP
+	FT_LOAD_VERTICAL_LAYOUT             FtLoadFlags = C.FT_LOAD_VERTICAL_LAYOUT
+	FT_LOAD_FORCE_AUTOHINT              FtLoadFlags = C.FT_LOAD_FORCE_AUTOHINT
+	FT_LOAD_PEDANTIC                    FtLoadFlags = C.FT_LOAD_PEDANTIC
+	FT_LOAD_IGNORE_GLOBAL_ADVANCE_WIDTH FtLoadFlags = C.FT_LOAD_IGNORE_GLOBAL_ADVANCE_WIDTH
+	FT_LOAD_MONOCHROME                  FtLoadFlags = C.FT_LOAD_MONOCHROME
+	FT_LOAD_LINEAR_DESIGN               FtLoadFlags = C.FT_LOAD_LINEAR_DESIGN
+	FT_LOAD_NO_AUTOHINT                 FtLoadFlags = C.FT_LOAD_NO_AUTOHINT
+	FT_LOAD_COLOR                       FtLoadFlags = C.FT_LOAD_COLOR
+	FT_LOAD_TARGET_NORMAL               FtLoadFlags = C.FT_LOAD_TARGET_NORMAL
+	FT_LOAD_TARGET_LIGHT                FtLoadFlags = C.FT_LOAD_TARGET_LIGHT
+	FT_LOAD_TARGET_MONO                 FtLoadFlags = C.FT_LOAD_TARGET_MONO
+	FT_LOAD_TARGET_LCD                  FtLoadFlags = C.FT_LOAD_TARGET_LCD
+	FT_LOAD_TARGET_LCD_V                FtLoadFlags = C.FT_LOAD_TARGET_LCD_V
+)
+
+// cairo_ft_synthesize_t
+type FtSynthesize int
+
+const (
+	FT_SYNTHESIZE_BOLD    FtSynthesize = C.CAIRO_FT_SYNTHESIZE_BOLD
+	FT_SYNTHESIZE_OBLIQUE FtSynthesize = C.CAIRO_FT_SYNTHESIZE_OBLIQUE
+)
+
 // Initialize a new FreeType library object
+//
+// All Cairo_freetype values share one reference counted FreeType library.
 func InitFreeType() (Cairo_freetype, error) {
-	var library C.FT_Library
-	err := C.FT_Init_FreeType(&library)
-	if err == 0 {
-		return Cairo_freetype{library: library}, nil
+	if _, err := sharedFtLibrary.acquire(); err != nil {
+		return Cairo_freetype{}, err
 	}
-	return Cairo_freetype{}, fmt.Errorf("FT_Init_FreeType error %v", err)
+	return Cairo_freetype{handle: &ftHandle{}}, nil
 }
 
 // Destroy a given FreeType library object and all of its children, including resources, drivers, faces, sizes, etc.
-func (ft Cairo_freetype) DoneFreeType() error {
-	if ft.library != nil {
-		err := C.FT_Done_FreeType(ft.library)
-		ft.library = nil
-		if err != 0 {
-			return fmt.Errorf("FT_Done_FreeType error %v", err)
+//
+// The shared library is only freed after the last FontFace created with it
+// has been destroyed, so faces stay usable after calling DoneFreeType.
+// Copies of ft are done too, calling DoneFreeType again is a no-op.
+func (ft *Cairo_freetype) DoneFreeType() error {
+	if ft.handle == nil {
+		return nil
+	}
+	return ft.handle.release()
+}
+
+// newFtFontFace creates a font face with newFace, the FreeType
+// function name for errors, and ties the lifetime of the FreeType face
+// to the cairo font face. loadFlags are passed to cairo
+// for loading glyphs. coords are variable font design coordinates,
+// they are set before cairo copies them when creating the font face.
+// data is C memory used by the face, it is freed with the face
+// or if an error is returned.
+func (ft Cairo_freetype) newFtFontFace(name string, data unsafe.Pointer, loadFlags FtLoadFlags, coords []float64, newFace func(library C.FT_Library, face *C.FT_Face) C.FT_Error) (*FontFace, error) {
+	if !ft.initialized() {
+		C.free(data)
+		return nil, fmt.Errorf("FreeType library not initialized")
+	}
+	library, err := sharedFtLibrary.acquire()
+	if err != nil {
+		C.free(data)
+		return nil, err
+	}
+	face := &ftFace{library: &sharedFtLibrary, data: data}
+	sharedFtLibrary.mutex.Lock()
+	ftErr := newFace(library, &face.face)
+	if ftErr == 0 && len(coords) > 0 {
+		if ftErr = ftSetVarDesignCoordinates(face.face, coords); ftErr != 0 {
+			C.FT_Done_Face(face.face)
+			name = "FT_Set_Var_Design_Coordinates"
 		}
 	}
-	return nil
+	sharedFtLibrary.mutex.Unlock()
+	if ftErr != 0 {
+		C.free(data)
+		sharedFtLibrary.release()
+		return nil, fmt.Errorf("%s error %v", name, ftErr)
+	}
+
+	fontFace := newFontFace(C.cairo_ft_font_face_create_for_ft_face(face.face, C.int(loadFlags)))
+	handle := cgo.NewHandle(face)
+	if status := Status(C.go_cairo_ft_font_face_attach(fontFace.face, C.uintptr_t(handle))); status != STATUS_SUCCESS {
+		// fontFace is an error font face, or a valid font face that could
+		// not store the user data. In both cases cairo won't release
+		// the FreeType face, but a valid font face still uses it,
+		// so fontFace has to be destroyed first.
+		err := statusError(fontFace.Status())
+		if err == nil {
+			err = status
+		}
+		fontFace.Destroy()
+		handle.Delete()
+		face.done()
+		return nil, err
+	}
+	return fontFace, nil
+}
+
+// ftFace returns the FreeType face of a font face created
+// by Cairo_freetype, or nil for other font faces.
+func (ff *FontFace) ftFace() *ftFace {
+	defer runtime.KeepAlive(ff)
+	data := C.go_cairo_ft_font_face_data(ff.face)
+	if data == 0 {
+		return nil
+	}
+	return cgo.Handle(data).Value().(*ftFace)
 }
 
 // Call FT_Open_Face to open a font by its pathname
 //
+// The FreeType face is released after the returned FontFace
+// and everything using it (scaled fonts, surfaces) has been destroyed.
+//
 // Example:
 //
 //	//  Initialize a new FreeType library object
@@ -59,49 +275,143 @@ func (ft Cairo_freetype) DoneFreeType() error {
 //	// Use the font:
 //	surface.SetFontFace(myfont)
 func (ft Cairo_freetype) FtNewFace(filename string) (*FontFace, error) {
-	var face C.FT_Face
-	cs := C.CString(filename)
-	err := C.FT_New_Face(ft.library, cs, 0, &face)
-	C.free(unsafe.Pointer(cs))
-	if err != 0 {
-		return nil, fmt.Errorf("FT_New_Face error %v", err)
-	}
+	return ft.FtNewFaceIndex(filename, 0, FT_LOAD_DEFAULT)
+}
 
-	return &FontFace{
-		face:    C.cairo_ft_font_face_create_for_ft_face(face, 0),
-		ft_face: &face,
-	}, nil
+// FtNewFaceIndex opens the face with index of a font collection
+// (.ttc or .otc) file, see FtCollectionFaces.
+// loadFlags are used by cairo to load the glyphs of the face.
+func (ft Cairo_freetype) FtNewFaceIndex(filename string, index int, loadFlags FtLoadFlags) (*FontFace, error) {
+	return ft.FtNewVarFace(filename, index, loadFlags, nil)
+}
+
+// FtNewVarFace opens the face with index like FtNewFaceIndex and selects
+// the instance of a variable font with coords, the design coordinates
+// in the order of FtVarAxes. Missing coordinates are set to the default
+// of their axis. The instance can't be changed after the face has been
+// created, use FontOptions.SetVariations to draw other instances.
+func (ft Cairo_freetype) FtNewVarFace(filename string, index int, loadFlags FtLoadFlags, coords []float64) (*FontFace, error) {
+	cs := C.CString(filename)
+	defer C.free(unsafe.Pointer(cs))
+	return ft.newFtFontFace("FT_New_Face", nil, loadFlags, coords, func(library C.FT_Library, face *C.FT_Face) C.FT_Error {
+		return C.FT_New_Face(library, cs, C.FT_Long(index), face)
+	})
 }
 
 // Call FT_Open_Face to open a font that has been loaded into memory
 //
 // This is similar to FtNewFace, but loads the font from memory instead
-// of from file
+// of from file. data is copied to C memory that is freed together
+// with the FreeType face, so it can be reused after the call.
 func (ft Cairo_freetype) FtNewMemoryFace(data []byte) (*FontFace, error) {
-	var face C.FT_Face
-	err := C.FT_New_Memory_Face(ft.library, (*C.uchar)(&data[0]), C.long(len(data)), 0, &face)
-	if err != 0 {
-		return nil, fmt.Errorf("FT_New_Memory_Face error %v", err)
+	return ft.FtNewMemoryFaceIndex(data, 0, FT_LOAD_DEFAULT)
+}
+
+// FtNewMemoryFaceIndex opens the face with index of font collection data,
+// see FtNewFaceIndex and FtNewMemoryFace.
+func (ft Cairo_freetype) FtNewMemoryFaceIndex(data []byte, index int, loadFlags FtLoadFlags) (*FontFace, error) {
+	return ft.FtNewMemoryVarFace(data, index, loadFlags, nil)
+}
+
+// FtNewMemoryVarFace opens the face with index of font data
+// and selects the instance of a variable font with coords,
+// see FtNewVarFace and FtNewMemoryFace.
+func (ft Cairo_freetype) FtNewMemoryVarFace(data []byte, index int, loadFlags FtLoadFlags, coords []float64) (*FontFace, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("FT_New_Memory_Face error: empty font data")
 	}
+	cdata := C.CBytes(data)
+	return ft.newFtFontFace("FT_New_Memory_Face", cdata, loadFlags, coords, func(library C.FT_Library, face *C.FT_Face) C.FT_Error {
+		return C.FT_New_Memory_Face(library, (*C.FT_Byte)(cdata), C.FT_Long(len(data)), C.FT_Long(index), face)
+	})
+}
 
-	return &FontFace{
-		face:    C.cairo_ft_font_face_create_for_ft_face(face, 0),
-		ft_face: &face,
-	}, nil
+// FtFaceInfo describes a face of a font file or collection.
+type FtFaceInfo struct {
+	Index      int
+	FamilyName string
+	StyleName  string
 }
 
-// Discard a given face object, as well as all of its child slots and sizes
-func (ff *FontFace) FtDoneFace() error {
-	if ff.face != nil {
-		C.cairo_font_face_destroy(ff.face)
-		ff.face = nil
-	}
-	if ff.ft_face != nil {
-		err := C.FT_Done_Face(*ff.ft_face)
-		ff.ft_face = nil
-		if err != 0 {
-			return fmt.Errorf("FT_Done_Face error %v", err)
+// FtCollectionFaces returns the faces of a font collection (.ttc or .otc) file.
+// For other font files it returns the single face with index 0.
+func (ft Cairo_freetype) FtCollectionFaces(filename string) ([]FtFaceInfo, error) {
+	cs := C.CString(filename)
+	defer C.free(unsafe.Pointer(cs))
+	return ft.collectionFaces("FT_New_Face", func(library C.FT_Library, index int, face *C.FT_Face) C.FT_Error {
+		return C.FT_New_Face(library, cs, C.FT_Long(index), face)
+	})
+}
+
+// FtMemoryCollectionFaces returns the faces of font collection data,
+// see FtCollectionFaces.
+func (ft Cairo_freetype) FtMemoryCollectionFaces(data []byte) ([]FtFaceInfo, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("FT_New_Memory_Face error: empty font data")
+	}
+	cdata := C.CBytes(data)
+	defer C.free(cdata)
+	return ft.collectionFaces("FT_New_Memory_Face", func(library C.FT_Library, index int, face *C.FT_Face) C.FT_Error {
+		return C.FT_New_Memory_Face(library, (*C.FT_Byte)(cdata), C.FT_Long(len(data)), C.FT_Long(index), face)
+	})
+}
+
+// collectionFaces opens every face of a collection with newFace
+// to read its names.
+func (ft Cairo_freetype) collectionFaces(name string, newFace func(library C.FT_Library, index int, face *C.FT_Face) C.FT_Error) ([]FtFaceInfo, error) {
+	if !ft.initialized() {
+		return nil, fmt.Errorf("FreeType library not initialized")
+	}
+	// Hold a reference, so the library is not freed
+	// by a concurrent DoneFreeType during the scan
+	library, err := sharedFtLibrary.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer sharedFtLibrary.release()
+	sharedFtLibrary.mutex.Lock()
+	defer sharedFtLibrary.mutex.Unlock()
+
+	var faces []FtFaceInfo
+	for index, numFaces := 0, 1; index < numFaces; index++ {
+		var face C.FT_Face
+		if err := newFace(library, index, &face); err != 0 {
+			return nil, fmt.Errorf("%s error %v", name, err)
 		}
+		numFaces = int(face.num_faces)
+		faces = append(faces, FtFaceInfo{
+			Index:      index,
+			FamilyName: C.GoString(face.family_name),
+			StyleName:  C.GoString(face.style_name),
+		})
+		C.FT_Done_Face(face)
 	}
+	return faces, nil
+}
+
+// Discard a given face object, as well as all of its child slots and sizes
+//
+// FtDoneFace destroys the FontFace, the FreeType face is released
+// when cairo does not use the face anymore.
+func (ff *FontFace) FtDoneFace() error {
+	ff.Destroy()
 	return nil
 }
+
+// FtSetSynthesize enables synthetic bold or oblique glyphs
+// for a FONT_TYPE_FT font face that lacks them.
+func (ff *FontFace) FtSetSynthesize(flags FtSynthesize) {
+	defer runtime.KeepAlive(ff)
+	C.cairo_ft_font_face_set_synthesize(ff.face, C.uint(flags))
+}
+
+// FtUnsetSynthesize disables synthesizing of flags.
+func (ff *FontFace) FtUnsetSynthesize(flags FtSynthesize) {
+	defer runtime.KeepAlive(ff)
+	C.cairo_ft_font_face_unset_synthesize(ff.face, C.uint(flags))
+}
+
+func (ff *FontFace) FtGetSynthesize() FtSynthesize {
+	defer runtime.KeepAlive(ff)
+	return FtSynthesize(C.cairo_ft_font_face_get_synthesize(ff.face))
+}
@@ -0,0 +1,166 @@
+//go:build !goci
+// +build !goci
+
+package cairo
+
+import (
+	"os"
+	"testing"
+)
+
+const testFontFile = "/usr/share/fonts/truetype/dejavu/DejaVuSerif.ttf"
+
+func skipWithoutTestFont(t *testing.T) {
+	t.Helper()
+	if _, err := os.Stat(testFontFile); err != nil {
+		t.Skip(err)
+	}
+}
+
+func ftLibraryRefs() int {
+	sharedFtLibrary.mutex.Lock()
+	defer sharedFtLibrary.mutex.Unlock()
+	return sharedFtLibrary.refs
+}
+
+func TestFtLibraryRelease(t *testing.T) {
+	var library ftLibrary
+	if _, err := library.acquire(); err != nil {
+		t.Fatal(err)
+	}
+	if err := library.release(); err != nil {
+		t.Fatal(err)
+	}
+	if err := library.release(); err == nil {
+		t.Error("expected error for release without acquire")
+	}
+	if library.refs != 0 {
+		t.Errorf("refs is %d after unbalanced release, expected 0", library.refs)
+	}
+}
+
+func TestFtCollectionFaces(t *testing.T) {
+	skipWithoutTestFont(t)
+	ft, err := InitFreeType()
+	if err != nil {
+		t.Fatal(err)
+	}
+	refs := ftLibraryRefs()
+
+	faces, err := ft.FtCollectionFaces(testFontFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(faces) != 1 || faces[0].Index != 0 || faces[0].FamilyName != "DejaVu Serif" {
+		t.Errorf("unexpected faces %v", faces)
+	}
+	if _, err := ft.FtCollectionFaces("does-not-exist.ttf"); err == nil {
+		t.Error("expected error for missing file")
+	}
+	if r := ftLibraryRefs(); r != refs {
+		t.Errorf("library has %d references after scanning, expected %d", r, refs)
+	}
+
+	if err := ft.DoneFreeType(); err != nil {
+		t.Fatal(err)
+	}
+	if err := ft.DoneFreeType(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ft.FtCollectionFaces(testFontFile); err == nil {
+		t.Error("expected error after DoneFreeType")
+	}
+}
+
+func TestFtFaceOfReferencedFontFace(t *testing.T) {
+	skipWithoutTestFont(t)
+	ft, err := InitFreeType()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ft.DoneFreeType()
+	fontFace, err := ft.FtNewFace(testFontFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fontFace.Destroy()
+
+	var identity Matrix
+	identity.InitIdendity()
+	scaledFont := NewScaledFont(fontFace, identity, identity, nil)
+	defer scaledFont.Destroy()
+	reference := fontFace.Reference()
+	defer reference.Destroy()
+	fromScaledFont := scaledFont.GetFontFace()
+	defer fromScaledFont.Destroy()
+
+	for _, face := range []*FontFace{fontFace, reference, fromScaledFont} {
+		if face.ftFace() == nil {
+			t.Fatal("FontFace has no FreeType face")
+		}
+		// DejaVu Serif is no variable font
+		axes, err := face.FtVarAxes()
+		if err != nil || len(axes) != 0 {
+			t.Errorf("FtVarAxes() returned %v, %v", axes, err)
+		}
+		instances, err := face.FtNamedInstances()
+		if err != nil || len(instances) != 0 {
+			t.Errorf("FtNamedInstances() returned %v, %v", instances, err)
+		}
+	}
+
+	toyFace := NewToyFontFace("serif", FONT_SLANT_NORMAL, FONT_WEIGHT_NORMAL)
+	defer toyFace.Destroy()
+	if _, err := toyFace.FtVarAxes(); err == nil {
+		t.Error("expected error for toy font face")
+	}
+}
+
+func TestFtNewVarFaceOfStaticFont(t *testing.T) {
+	skipWithoutTestFont(t)
+	ft, err := InitFreeType()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ft.DoneFreeType()
+	refs := ftLibraryRefs()
+
+	if _, err := ft.FtNewVarFace(testFontFile, 0, FT_LOAD_DEFAULT, []float64{700}); err == nil {
+		t.Error("expected error for design coordinates of a static font")
+	}
+	if r := ftLibraryRefs(); r != refs {
+		t.Errorf("library has %d references after failing, expected %d", r, refs)
+	}
+}
+
+func TestFtDoneFreeTypeOfCopies(t *testing.T) {
+	skipWithoutTestFont(t)
+	refs := ftLibraryRefs()
+	ft, err := InitFreeType()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fontFace, err := ft.FtNewFace(testFontFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	copied := ft
+	if err := ft.DoneFreeType(); err != nil {
+		t.Fatal(err)
+	}
+	if err := copied.DoneFreeType(); err != nil {
+		t.Fatal(err)
+	}
+	if r := ftLibraryRefs(); r != refs+1 {
+		t.Errorf("library has %d references with a live face, expected %d", r, refs+1)
+	}
+	if _, err := copied.FtNewFace(testFontFile); err == nil {
+		t.Error("expected error for a copy after DoneFreeType")
+	}
+
+	fontFace.Destroy()
+	if r := ftLibraryRefs(); r != refs {
+		t.Errorf("library has %d references after destroying the face, expected %d", r, refs)
+	}
+}
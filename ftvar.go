@@ -0,0 +1,179 @@
+//go:build !goci
+// +build !goci
+
+package cairo
+
+/*
+#include <cairo/cairo-ft.h>
+#include <ft2build.h>
+#include FT_MULTIPLE_MASTERS_H
+#include FT_SFNT_NAMES_H
+#include FT_TRUETYPE_IDS_H
+*/
+import "C"
+
+import (
+	"fmt"
+	"math"
+	"unicode/utf16"
+	"unsafe"
+)
+
+// FtVarAxis is a design axis of a variable font, like "wght" or "wdth".
+// The values are in design units, for example 100 to 900 for "wght".
+type FtVarAxis struct {
+	Tag     string
+	Name    string
+	Minimum float64
+	Default float64
+	Maximum float64
+}
+
+// FtNamedInstance is a named instance of a variable font like "Bold Condensed".
+// Index starts at 1, it selects the instance when passed
+// as (Index << 16) | faceIndex to FtNewFaceIndex.
+// Coords has a design coordinate for every axis.
+type FtNamedInstance struct {
+	Index  int
+	Name   string
+	Coords []float64
+}
+
+func fromFtFixed(f C.FT_Fixed) float64 {
+	return float64(f) / 65536
+}
+
+func toFtFixed(f float64) C.FT_Fixed {
+	return C.FT_Fixed(math.Round(f * 65536))
+}
+
+// ftTag converts an OpenType tag to a string like "wght".
+func ftTag(tag C.FT_ULong) string {
+	return string([]byte{byte(tag >> 24), byte(tag >> 16), byte(tag >> 8), byte(tag)})
+}
+
+// ftLockedFace returns the FreeType face of a font face created
+// by Cairo_freetype, locked by cairo so cairo doesn't use it concurrently.
+// Call the returned unlock function after using the face.
+func (ff *FontFace) ftLockedFace() (face C.FT_Face, library C.FT_Library, unlock func(), err error) {
+	ftFace := ff.ftFace()
+	if ftFace == nil {
+		return nil, nil, nil, fmt.Errorf("FontFace has no FreeType face")
+	}
+	var identity Matrix
+	identity.InitIdendity()
+	scaledFont := NewScaledFont(ff, identity, identity, nil)
+	face = C.cairo_ft_scaled_font_lock_face(scaledFont.scaledFont)
+	if face == nil {
+		err = fmt.Errorf("cairo_ft_scaled_font_lock_face error %v", scaledFont.Status())
+		scaledFont.Destroy()
+		return nil, nil, nil, err
+	}
+	unlock = func() {
+		C.cairo_ft_scaled_font_unlock_face(scaledFont.scaledFont)
+		scaledFont.Destroy()
+	}
+	// The face holds a reference of the library
+	return face, ftFace.library.library, unlock, nil
+}
+
+// ftMMVar calls f with the variation data of the FreeType face.
+// f is not called for faces that are no variable fonts.
+func (ff *FontFace) ftMMVar(f func(face C.FT_Face, mmVar *C.FT_MM_Var) error) error {
+	face, library, unlock, err := ff.ftLockedFace()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if face.face_flags&C.FT_FACE_FLAG_MULTIPLE_MASTERS == 0 {
+		return nil
+	}
+	var mmVar *C.FT_MM_Var
+	if err := C.FT_Get_MM_Var(face, &mmVar); err != 0 {
+		return fmt.Errorf("FT_Get_MM_Var error %v", err)
+	}
+	defer C.FT_Done_MM_Var(library, mmVar)
+	return f(face, mmVar)
+}
+
+// FtVarAxes returns the design axes of a variable font created
+// by Cairo_freetype, or no axes if the face is no variable font.
+// Instances are selected when creating the face with FtNewVarFace,
+// or for a scaled font with FontOptions.SetVariations.
+func (ff *FontFace) FtVarAxes() (axes []FtVarAxis, err error) {
+	err = ff.ftMMVar(func(face C.FT_Face, mmVar *C.FT_MM_Var) error {
+		for _, axis := range unsafe.Slice(mmVar.axis, int(mmVar.num_axis)) {
+			name := ftSfntName(face, axis.strid)
+			if name == "" {
+				name = C.GoString(axis.name)
+			}
+			axes = append(axes, FtVarAxis{
+				Tag:     ftTag(axis.tag),
+				Name:    name,
+				Minimum: fromFtFixed(axis.minimum),
+				Default: fromFtFixed(axis.def),
+				Maximum: fromFtFixed(axis.maximum),
+			})
+		}
+		return nil
+	})
+	return axes, err
+}
+
+// FtNamedInstances returns the named instances of a variable font,
+// or no instances if the face is no variable font.
+func (ff *FontFace) FtNamedInstances() (instances []FtNamedInstance, err error) {
+	err = ff.ftMMVar(func(face C.FT_Face, mmVar *C.FT_MM_Var) error {
+		numAxis := int(mmVar.num_axis)
+		for i, style := range unsafe.Slice(mmVar.namedstyle, int(mmVar.num_namedstyles)) {
+			instance := FtNamedInstance{
+				Index:  i + 1,
+				Name:   ftSfntName(face, style.strid),
+				Coords: make([]float64, numAxis),
+			}
+			for j, coord := range unsafe.Slice(style.coords, numAxis) {
+				instance.Coords[j] = fromFtFixed(coord)
+			}
+			instances = append(instances, instance)
+		}
+		return nil
+	})
+	return instances, err
+}
+
+// ftSetVarDesignCoordinates selects an instance of a variable font
+// by design coordinates, the caller has to lock the face.
+// coords must not be empty.
+func ftSetVarDesignCoordinates(face C.FT_Face, coords []float64) C.FT_Error {
+	fixed := make([]C.FT_Fixed, len(coords))
+	for i, coord := range coords {
+		fixed[i] = toFtFixed(coord)
+	}
+	return C.FT_Set_Var_Design_Coordinates(face, C.FT_UInt(len(fixed)), &fixed[0])
+}
+
+// ftSfntName returns the English name with nameID from the
+// SFNT name table of face, or an empty string if there is none.
+func ftSfntName(face C.FT_Face, nameID C.FT_UInt) string {
+	var name string
+	count := C.FT_Get_Sfnt_Name_Count(face)
+	for i := C.FT_UInt(0); i < count; i++ {
+		var sfntName C.FT_SfntName
+		if C.FT_Get_Sfnt_Name(face, i, &sfntName) != 0 || C.FT_UInt(sfntName.name_id) != nameID {
+			continue
+		}
+		data := C.GoBytes(unsafe.Pointer(sfntName.string), C.int(sfntName.string_len))
+		switch {
+		case sfntName.platform_id == C.TT_PLATFORM_MICROSOFT && sfntName.language_id == C.TT_MS_LANGID_ENGLISH_UNITED_STATES:
+			// UTF-16BE, preferred
+			u := make([]uint16, len(data)/2)
+			for j := range u {
+				u[j] = uint16(data[2*j])<<8 | uint16(data[2*j+1])
+			}
+			return string(utf16.Decode(u))
+		case sfntName.platform_id == C.TT_PLATFORM_MACINTOSH && sfntName.encoding_id == C.TT_MAC_ID_ROMAN && name == "":
+			name = string(data)
+		}
+	}
+	return name
+}
@@ -0,0 +1,123 @@
+//go:build !goci
+// +build !goci
+
+package cairo
+
+// #include <cairo/cairo.h>
+import "C"
+
+import (
+	"unsafe"
+)
+
+// cairoGlyphs converts glyphs to a cairo_glyph_t array in Go memory,
+// so no cairo_glyph_allocate/cairo_glyph_free is needed.
+// It returns nil for no glyphs.
+func cairoGlyphs(glyphs []Glyph) []C.cairo_glyph_t {
+	if len(glyphs) == 0 {
+		return nil
+	}
+	cglyphs := make([]C.cairo_glyph_t, len(glyphs))
+	for i, g := range glyphs {
+		cglyphs[i] = C.cairo_glyph_t{
+			index: C.ulong(g.Index),
+			x:     C.double(g.X),
+			y:     C.double(g.Y),
+		}
+	}
+	return cglyphs
+}
+
+// goGlyphs copies num glyphs starting at cglyphs.
+func goGlyphs(cglyphs *C.cairo_glyph_t, num int) []Glyph {
+	if cglyphs == nil || num <= 0 {
+		return nil
+	}
+	glyphs := make([]Glyph, num)
+	for i, g := range unsafe.Slice(cglyphs, num) {
+		glyphs[i] = Glyph{
+			Index: uint64(g.index),
+			X:     float64(g.x),
+			Y:     float64(g.y),
+		}
+	}
+	return glyphs
+}
+
+// cairoTextClusters converts clusters to a cairo_text_cluster_t array
+// in Go memory. It returns nil for no clusters.
+func cairoTextClusters(clusters []TextCluster) []C.cairo_text_cluster_t {
+	if len(clusters) == 0 {
+		return nil
+	}
+	cclusters := make([]C.cairo_text_cluster_t, len(clusters))
+	for i, c := range clusters {
+		cclusters[i] = C.cairo_text_cluster_t{
+			num_bytes:  C.int(c.NumBytes),
+			num_glyphs: C.int(c.NumGlyphs),
+		}
+	}
+	return cclusters
+}
+
+// goTextClusters copies num clusters starting at cclusters.
+func goTextClusters(cclusters *C.cairo_text_cluster_t, num int) []TextCluster {
+	if cclusters == nil || num <= 0 {
+		return nil
+	}
+	clusters := make([]TextCluster, num)
+	for i, c := range unsafe.Slice(cclusters, num) {
+		clusters[i] = TextCluster{
+			NumBytes:  int(c.num_bytes),
+			NumGlyphs: int(c.num_glyphs),
+		}
+	}
+	return clusters
+}
+
+// firstGlyph returns a pointer to the first element of cglyphs or nil.
+func firstGlyph(cglyphs []C.cairo_glyph_t) *C.cairo_glyph_t {
+	if len(cglyphs) == 0 {
+		return nil
+	}
+	return &cglyphs[0]
+}
+
+func newTextExtents(cte *C.cairo_text_extents_t) *TextExtents {
+	return &TextExtents{
+		Xbearing: float64(cte.x_bearing),
+		Ybearing: float64(cte.y_bearing),
+		Width:    float64(cte.width),
+		Height:   float64(cte.height),
+		Xadvance: float64(cte.x_advance),
+		Yadvance: float64(cte.y_advance),
+	}
+}
+
+func newFontExtents(cfe *C.cairo_font_extents_t) *FontExtents {
+	return &FontExtents{
+		Ascent:      float64(cfe.ascent),
+		Descent:     float64(cfe.descent),
+		Height:      float64(cfe.height),
+		MaxXadvance: float64(cfe.max_x_advance),
+		MaxYadvance: float64(cfe.max_y_advance),
+	}
+}
+
+// unsafeGlyphs returns num glyphs starting at cglyphs as slice
+// without copying, for cglyphs == nil the slice is empty.
+func unsafeGlyphs(cglyphs *C.cairo_glyph_t, num int) []C.cairo_glyph_t {
+	if cglyphs == nil || num <= 0 {
+		return nil
+	}
+	return unsafe.Slice(cglyphs, num)
+}
+
+// unsafeTextClusters returns num clusters starting at cclusters as slice
+// without copying, for cclusters == nil the slice is empty.
+func unsafeTextClusters(cclusters *C.cairo_text_cluster_t, num int) []C.cairo_text_cluster_t {
+	if cclusters == nil || num <= 0 {
+		return nil
+	}
+	return unsafe.Slice(cclusters, num)
+}
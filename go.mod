@@ -1,3 +1,5 @@
 module github.com/ungerik/go-cairo
 
-go 1.13
+go 1.17
+
+require golang.org/x/image v0.18.0
@@ -0,0 +1,155 @@
+//go:build !goci
+// +build !goci
+
+package cairo
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+type refCounted interface {
+	GetReferenceCount() int
+	Status() Status
+	Destroy()
+}
+
+type lifetimeTest struct {
+	name string
+	new  func() refCounted
+	// reference returns a new reference of obj
+	reference func(obj refCounted) refCounted
+	// refsPerReference is the number of references a reference adds
+	refsPerReference int
+}
+
+func lifetimeTests() []lifetimeTest {
+	var identity Matrix
+	identity.InitIdendity()
+	return []lifetimeTest{
+		{
+			name: "Context",
+			new: func() refCounted {
+				surface := NewSurface(FORMAT_ARGB32, 4, 4)
+				defer surface.Destroy()
+				return NewContext(surface)
+			},
+			reference:        func(obj refCounted) refCounted { return obj.(*Context).Reference() },
+			refsPerReference: 1,
+		},
+		{
+			name: "Surface",
+			new:  func() refCounted { return NewSurface(FORMAT_ARGB32, 4, 4) },
+			// The new Surface and its default context both reference the cairo surface
+			reference:        func(obj refCounted) refCounted { return obj.(*Surface).Reference() },
+			refsPerReference: 2,
+		},
+		{
+			name:             "Pattern",
+			new:              func() refCounted { return NewPatternRGBA(1, 0, 0, 0.5) },
+			reference:        func(obj refCounted) refCounted { return obj.(*Pattern).Reference() },
+			refsPerReference: 1,
+		},
+		{
+			name:             "FontFace",
+			new:              func() refCounted { return NewToyFontFace("sans-serif", FONT_SLANT_NORMAL, FONT_WEIGHT_BOLD) },
+			reference:        func(obj refCounted) refCounted { return obj.(*FontFace).Reference() },
+			refsPerReference: 1,
+		},
+		{
+			name: "ScaledFont",
+			new: func() refCounted {
+				fontFace := NewToyFontFace("serif", FONT_SLANT_ITALIC, FONT_WEIGHT_NORMAL)
+				defer fontFace.Destroy()
+				return NewScaledFont(fontFace, identity, identity, nil)
+			},
+			reference:        func(obj refCounted) refCounted { return obj.(*ScaledFont).Reference() },
+			refsPerReference: 1,
+		},
+	}
+}
+
+func TestDestroyIsIdempotent(t *testing.T) {
+	for _, test := range lifetimeTests() {
+		obj := test.new()
+		if status := obj.Status(); status != STATUS_SUCCESS {
+			t.Fatalf("%s: new object has status %s", test.name, status)
+		}
+		obj.Destroy()
+		obj.Destroy()
+		if status := obj.Status(); status != STATUS_NULL_POINTER {
+			t.Errorf("%s: Status() after Destroy() is %s, expected STATUS_NULL_POINTER", test.name, status)
+		}
+		// The finalizer was removed by Destroy, so collecting
+		// the object must not destroy it a second time.
+		obj = nil
+		runtime.GC()
+		runtime.GC()
+	}
+}
+
+func TestReferenceDestroyBalance(t *testing.T) {
+	for _, test := range lifetimeTests() {
+		obj := test.new()
+		count := obj.GetReferenceCount()
+		if count < 1 {
+			t.Fatalf("%s: new object has reference count %d", test.name, count)
+		}
+		ref1 := test.reference(obj)
+		ref2 := test.reference(ref1)
+		if got, want := obj.GetReferenceCount(), count+2*test.refsPerReference; got != want {
+			t.Errorf("%s: reference count %d after two References, expected %d", test.name, got, want)
+		}
+		if ref2.GetReferenceCount() != obj.GetReferenceCount() {
+			t.Errorf("%s: references report different counts", test.name)
+		}
+		ref1.Destroy()
+		ref1.Destroy()
+		if got, want := ref2.GetReferenceCount(), count+test.refsPerReference; got != want {
+			t.Errorf("%s: reference count %d after destroying one reference twice, expected %d", test.name, got, want)
+		}
+		obj.Destroy()
+		if got, want := ref2.GetReferenceCount(), test.refsPerReference; got != want {
+			t.Errorf("%s: reference count %d after destroying the original, expected %d", test.name, got, want)
+		}
+		if status := ref2.Status(); status != STATUS_SUCCESS {
+			t.Errorf("%s: remaining reference has status %s", test.name, status)
+		}
+		ref2.Destroy()
+	}
+}
+
+// referenceOnly returns a reference of a new object
+// that is unreachable after the function returns.
+//
+//go:noinline
+func referenceOnly(test lifetimeTest) refCounted {
+	return test.reference(test.new())
+}
+
+// waitFor runs the garbage collector until cond is true or a timeout.
+func waitFor(cond func() bool) bool {
+	for i := 0; i < 100; i++ {
+		runtime.GC()
+		if cond() {
+			return true
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return false
+}
+
+func TestFinalizerReleasesReference(t *testing.T) {
+	for _, test := range lifetimeTests() {
+		ref := referenceOnly(test)
+		count := ref.GetReferenceCount()
+		if !waitFor(func() bool { return ref.GetReferenceCount() < count }) {
+			t.Errorf("%s: finalizer did not release the reference", test.name)
+		}
+		if status := ref.Status(); status != STATUS_SUCCESS {
+			t.Errorf("%s: remaining reference has status %s", test.name, status)
+		}
+		ref.Destroy()
+	}
+}
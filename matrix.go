@@ -1,3 +1,4 @@
+//go:build !goci
 // +build !goci
 
 package cairo
@@ -47,8 +48,11 @@ func (self *Matrix) Rotate(radians float64) {
 	C.cairo_matrix_rotate(self.cairo_matrix_t(), C.double(radians))
 }
 
-func (self *Matrix) Invert() {
-	C.cairo_matrix_invert(self.cairo_matrix_t())
+// Invert inverts the matrix in place.
+// If the matrix is not invertible it is left unchanged
+// and STATUS_INVALID_MATRIX is returned.
+func (self *Matrix) Invert() error {
+	return statusError(Status(C.cairo_matrix_invert(self.cairo_matrix_t())))
 }
 
 func (self *Matrix) Multiply(a, b Matrix) {
@@ -0,0 +1,114 @@
+//go:build !goci
+// +build !goci
+
+package cairo
+
+// #include <cairo/cairo.h>
+import "C"
+
+import (
+	"runtime"
+)
+
+// NewPatternMesh creates a mesh pattern of tensor-product patches.
+// Patches are defined between MeshBeginPatch and MeshEndPatch
+// by a path of up to four sides starting with MeshMoveTo,
+// the colors of the four corners and optional control points.
+func NewPatternMesh() *Pattern {
+	return newPattern(C.cairo_pattern_create_mesh())
+}
+
+func (self *Pattern) MeshBeginPatch() {
+	defer runtime.KeepAlive(self)
+	C.cairo_mesh_pattern_begin_patch(self.pattern)
+}
+
+func (self *Pattern) MeshEndPatch() {
+	defer runtime.KeepAlive(self)
+	C.cairo_mesh_pattern_end_patch(self.pattern)
+}
+
+func (self *Pattern) MeshMoveTo(x, y float64) {
+	defer runtime.KeepAlive(self)
+	C.cairo_mesh_pattern_move_to(self.pattern, C.double(x), C.double(y))
+}
+
+func (self *Pattern) MeshLineTo(x, y float64) {
+	defer runtime.KeepAlive(self)
+	C.cairo_mesh_pattern_line_to(self.pattern, C.double(x), C.double(y))
+}
+
+func (self *Pattern) MeshCurveTo(x1, y1, x2, y2, x3, y3 float64) {
+	defer runtime.KeepAlive(self)
+	C.cairo_mesh_pattern_curve_to(self.pattern,
+		C.double(x1), C.double(y1),
+		C.double(x2), C.double(y2),
+		C.double(x3), C.double(y3))
+}
+
+// MeshSetControlPoint sets one of the four inner control points (0 to 3)
+// of the current patch.
+func (self *Pattern) MeshSetControlPoint(point int, x, y float64) {
+	defer runtime.KeepAlive(self)
+	C.cairo_mesh_pattern_set_control_point(self.pattern, C.uint(point), C.double(x), C.double(y))
+}
+
+// MeshSetCornerColorRGB sets the color of one of the four corners (0 to 3)
+// of the current patch.
+func (self *Pattern) MeshSetCornerColorRGB(corner int, red, green, blue float64) {
+	defer runtime.KeepAlive(self)
+	C.cairo_mesh_pattern_set_corner_color_rgb(self.pattern, C.uint(corner),
+		C.double(red), C.double(green), C.double(blue))
+}
+
+// MeshSetCornerColorRGBA sets the color of one of the four corners (0 to 3)
+// of the current patch.
+func (self *Pattern) MeshSetCornerColorRGBA(corner int, red, green, blue, alpha float64) {
+	defer runtime.KeepAlive(self)
+	C.cairo_mesh_pattern_set_corner_color_rgba(self.pattern, C.uint(corner),
+		C.double(red), C.double(green), C.double(blue), C.double(alpha))
+}
+
+// MeshGetPatchCount returns the number of completed patches.
+// The error is STATUS_PATTERN_TYPE_MISMATCH if the pattern is no mesh pattern.
+func (self *Pattern) MeshGetPatchCount() (int, error) {
+	defer runtime.KeepAlive(self)
+	var count C.uint
+	if err := statusError(Status(C.cairo_mesh_pattern_get_patch_count(self.pattern, &count))); err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+// MeshGetPath returns the path defining the sides of a patch.
+func (self *Pattern) MeshGetPath(patch int) (*Path, error) {
+	defer runtime.KeepAlive(self)
+	if patch < 0 {
+		return nil, STATUS_INVALID_INDEX
+	}
+	return newPathFromC(C.cairo_mesh_pattern_get_path(self.pattern, C.uint(patch)))
+}
+
+// MeshGetControlPoint returns one of the four inner control points
+// of a patch.
+func (self *Pattern) MeshGetControlPoint(patch, point int) (x, y float64, err error) {
+	defer runtime.KeepAlive(self)
+	if patch < 0 || point < 0 {
+		return 0, 0, STATUS_INVALID_INDEX
+	}
+	status := Status(C.cairo_mesh_pattern_get_control_point(self.pattern, C.uint(patch), C.uint(point),
+		(*C.double)(&x), (*C.double)(&y)))
+	return x, y, statusError(status)
+}
+
+// MeshGetCornerColorRGBA returns the color of one of the four corners
+// of a patch.
+func (self *Pattern) MeshGetCornerColorRGBA(patch, corner int) (red, green, blue, alpha float64, err error) {
+	defer runtime.KeepAlive(self)
+	if patch < 0 || corner < 0 {
+		return 0, 0, 0, 0, STATUS_INVALID_INDEX
+	}
+	status := Status(C.cairo_mesh_pattern_get_corner_color_rgba(self.pattern, C.uint(patch), C.uint(corner),
+		(*C.double)(&red), (*C.double)(&green), (*C.double)(&blue), (*C.double)(&alpha)))
+	return red, green, blue, alpha, statusError(status)
+}
@@ -0,0 +1,166 @@
+//go:build !goci
+// +build !goci
+
+package cairo
+
+// #include <cairo/cairo.h>
+// #include <stdlib.h>
+import "C"
+
+import (
+	"runtime"
+	"unsafe"
+)
+
+type Point struct {
+	X, Y float64
+}
+
+// PathSegment is one element of a Path.
+// PATH_MOVE_TO and PATH_LINE_TO have one point,
+// PATH_CURVE_TO has the two control points and the end point,
+// PATH_CLOSE_PATH has no points.
+type PathSegment struct {
+	Type   PathDataType
+	Points []Point
+}
+
+// Path is a Go copy of a cairo_path_t.
+// It does not hold any C memory, so it can be stored
+// and appended to any Context with AppendPath.
+type Path struct {
+	Segments []PathSegment
+}
+
+// numPoints returns the number of points of a segment of type t.
+func (t PathDataType) numPoints() int {
+	switch t {
+	case PATH_MOVE_TO, PATH_LINE_TO:
+		return 1
+	case PATH_CURVE_TO:
+		return 3
+	}
+	return 0
+}
+
+// cairo_path_data_t header as defined by cairo.h
+type pathDataHeader struct {
+	dataType C.cairo_path_data_type_t
+	length   C.int
+}
+
+// newPathFromC converts p to a Path and destroys p.
+func newPathFromC(p *C.cairo_path_t) (*Path, error) {
+	defer C.cairo_path_destroy(p)
+	if err := statusError(Status(p.status)); err != nil {
+		return nil, err
+	}
+	path := &Path{}
+	if p.num_data == 0 {
+		return path, nil
+	}
+	data := unsafe.Slice(p.data, int(p.num_data))
+	for i := 0; i < len(data); {
+		header := (*pathDataHeader)(unsafe.Pointer(&data[i]))
+		segment := PathSegment{Type: PathDataType(header.dataType)}
+		for j := 1; j < int(header.length); j++ {
+			point := (*[2]C.double)(unsafe.Pointer(&data[i+j]))
+			segment.Points = append(segment.Points, Point{float64(point[0]), float64(point[1])})
+		}
+		path.Segments = append(path.Segments, segment)
+		i += int(header.length)
+	}
+	return path, nil
+}
+
+func (self *Path) MoveTo(x, y float64) {
+	self.Segments = append(self.Segments, PathSegment{Type: PATH_MOVE_TO, Points: []Point{{x, y}}})
+}
+
+func (self *Path) LineTo(x, y float64) {
+	self.Segments = append(self.Segments, PathSegment{Type: PATH_LINE_TO, Points: []Point{{x, y}}})
+}
+
+func (self *Path) CurveTo(x1, y1, x2, y2, x3, y3 float64) {
+	self.Segments = append(self.Segments, PathSegment{Type: PATH_CURVE_TO, Points: []Point{{x1, y1}, {x2, y2}, {x3, y3}}})
+}
+
+func (self *Path) ClosePath() {
+	self.Segments = append(self.Segments, PathSegment{Type: PATH_CLOSE_PATH})
+}
+
+// Copy returns a deep copy of the path.
+func (self *Path) Copy() *Path {
+	path := &Path{Segments: make([]PathSegment, len(self.Segments))}
+	for i, segment := range self.Segments {
+		path.Segments[i] = PathSegment{Type: segment.Type, Points: append([]Point(nil), segment.Points...)}
+	}
+	return path
+}
+
+// Transform transforms all points of the path in place by matrix.
+func (self *Path) Transform(matrix Matrix) {
+	for _, segment := range self.Segments {
+		for i, p := range segment.Points {
+			segment.Points[i].X, segment.Points[i].Y = matrix.TransformPoint(p.X, p.Y)
+		}
+	}
+}
+
+// CopyPath returns a copy of the current path of the context
+// in user-space coordinates.
+func (self *Context) CopyPath() (*Path, error) {
+	defer runtime.KeepAlive(self)
+	return newPathFromC(C.cairo_copy_path(self.context))
+}
+
+// CopyPathFlat returns a copy of the current path of the context
+// in user-space coordinates with all curves flattened to line segments
+// with the current tolerance.
+func (self *Context) CopyPathFlat() (*Path, error) {
+	defer runtime.KeepAlive(self)
+	return newPathFromC(C.cairo_copy_path_flat(self.context))
+}
+
+// AppendPath appends path to the current path of the context.
+// The points of path are in user-space coordinates of the context.
+// STATUS_INVALID_PATH_DATA is returned without changing the current path
+// if a segment has an unknown type or not the number of points of its type.
+func (self *Context) AppendPath(path *Path) error {
+	defer runtime.KeepAlive(self)
+	numData := 0
+	for _, segment := range path.Segments {
+		if segment.Type < PATH_MOVE_TO || segment.Type > PATH_CLOSE_PATH ||
+			len(segment.Points) != segment.Type.numPoints() {
+			return STATUS_INVALID_PATH_DATA
+		}
+		numData += 1 + segment.Type.numPoints()
+	}
+	if numData == 0 {
+		return statusError(self.Status())
+	}
+
+	dataPtr := (*C.cairo_path_data_t)(C.malloc(C.size_t(numData) * C.sizeof_cairo_path_data_t))
+	defer C.free(unsafe.Pointer(dataPtr))
+	data := unsafe.Slice(dataPtr, numData)
+	i := 0
+	for _, segment := range path.Segments {
+		n := segment.Type.numPoints()
+		header := (*pathDataHeader)(unsafe.Pointer(&data[i]))
+		header.dataType = C.cairo_path_data_type_t(segment.Type)
+		header.length = C.int(1 + n)
+		for j, p := range segment.Points {
+			point := (*[2]C.double)(unsafe.Pointer(&data[i+1+j]))
+			point[0], point[1] = C.double(p.X), C.double(p.Y)
+		}
+		i += 1 + n
+	}
+
+	cpath := C.cairo_path_t{
+		status:   C.CAIRO_STATUS_SUCCESS,
+		data:     dataPtr,
+		num_data: C.int(numData),
+	}
+	C.cairo_append_path(self.context, &cpath)
+	return statusError(self.Status())
+}
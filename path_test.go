@@ -0,0 +1,41 @@
+//go:build !goci
+// +build !goci
+
+package cairo
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAppendPathValidatesSegments(t *testing.T) {
+	surface := NewSurface(FORMAT_ARGB32, 4, 4)
+	defer surface.Destroy()
+
+	tests := []struct {
+		name    string
+		segment PathSegment
+		valid   bool
+	}{
+		{"move to", PathSegment{PATH_MOVE_TO, []Point{{1, 2}}}, true},
+		{"line to", PathSegment{PATH_LINE_TO, []Point{{1, 2}}}, true},
+		{"curve to", PathSegment{PATH_CURVE_TO, []Point{{1, 2}, {3, 4}, {5, 6}}}, true},
+		{"close path", PathSegment{PATH_CLOSE_PATH, nil}, true},
+		{"move to without point", PathSegment{PATH_MOVE_TO, nil}, false},
+		{"line to with two points", PathSegment{PATH_LINE_TO, []Point{{1, 2}, {3, 4}}}, false},
+		{"curve to with one point", PathSegment{PATH_CURVE_TO, []Point{{1, 2}}}, false},
+		{"close path with point", PathSegment{PATH_CLOSE_PATH, []Point{{1, 2}}}, false},
+		{"unknown type", PathSegment{PathDataType(4), nil}, false},
+		{"negative type", PathSegment{PathDataType(-1), nil}, false},
+	}
+	for _, test := range tests {
+		path := &Path{Segments: []PathSegment{{PATH_MOVE_TO, []Point{{0, 0}}}, test.segment}}
+		err := surface.AppendPath(path)
+		if test.valid && err != nil {
+			t.Errorf("%s: unexpected error %v", test.name, err)
+		}
+		if !test.valid && !errors.Is(err, STATUS_INVALID_PATH_DATA) {
+			t.Errorf("%s: expected STATUS_INVALID_PATH_DATA, got %v", test.name, err)
+		}
+	}
+}
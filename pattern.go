@@ -0,0 +1,114 @@
+//go:build !goci
+// +build !goci
+
+package cairo
+
+// #include <cairo/cairo.h>
+import "C"
+
+import (
+	"image/color"
+	"runtime"
+)
+
+// ColorStop is a color stop of a linear or radial gradient pattern.
+type ColorStop struct {
+	Offset                  float64
+	Red, Green, Blue, Alpha float64
+}
+
+// colorToRGBA converts the alpha-premultiplied color c
+// to the non-premultiplied channels used by cairo.
+func colorToRGBA(c color.Color) (red, green, blue, alpha float64) {
+	r, g, b, a := c.RGBA()
+	if a == 0 {
+		return 0, 0, 0, 0
+	}
+	fa := float64(a)
+	return float64(r) / fa, float64(g) / fa, float64(b) / fa, fa / 0xffff
+}
+
+// NewPatternRGB creates an opaque solid pattern.
+func NewPatternRGB(red, green, blue float64) *Pattern {
+	return newPattern(C.cairo_pattern_create_rgb(C.double(red), C.double(green), C.double(blue)))
+}
+
+// NewPatternRGBA creates a solid pattern.
+func NewPatternRGBA(red, green, blue, alpha float64) *Pattern {
+	return newPattern(C.cairo_pattern_create_rgba(C.double(red), C.double(green), C.double(blue), C.double(alpha)))
+}
+
+// NewPatternColor creates a solid pattern with the color c.
+func NewPatternColor(c color.Color) *Pattern {
+	return NewPatternRGBA(colorToRGBA(c))
+}
+
+// AddColorStop adds a color stop with the color c to a gradient pattern.
+func (self *Pattern) AddColorStop(offset float64, c color.Color) {
+	red, green, blue, alpha := colorToRGBA(c)
+	self.AddColorStopRGBA(offset, red, green, blue, alpha)
+}
+
+func (self *Pattern) Type() PatternType {
+	defer runtime.KeepAlive(self)
+	return PatternType(C.cairo_pattern_get_type(self.pattern))
+}
+
+// GetColorStops returns the color stops of a gradient pattern.
+// The error is STATUS_PATTERN_TYPE_MISMATCH if the pattern is no gradient.
+func (self *Pattern) GetColorStops() ([]ColorStop, error) {
+	defer runtime.KeepAlive(self)
+	var count C.int
+	if err := statusError(Status(C.cairo_pattern_get_color_stop_count(self.pattern, &count))); err != nil {
+		return nil, err
+	}
+	stops := make([]ColorStop, int(count))
+	for i := range stops {
+		stop := &stops[i]
+		status := Status(C.cairo_pattern_get_color_stop_rgba(self.pattern, C.int(i),
+			(*C.double)(&stop.Offset),
+			(*C.double)(&stop.Red), (*C.double)(&stop.Green), (*C.double)(&stop.Blue), (*C.double)(&stop.Alpha)))
+		if err := statusError(status); err != nil {
+			return nil, err
+		}
+	}
+	return stops, nil
+}
+
+// GetLinearPoints returns the gradient vector of a linear gradient pattern.
+func (self *Pattern) GetLinearPoints() (l Linear, err error) {
+	defer runtime.KeepAlive(self)
+	status := Status(C.cairo_pattern_get_linear_points(self.pattern,
+		(*C.double)(&l.X0), (*C.double)(&l.Y0),
+		(*C.double)(&l.X1), (*C.double)(&l.Y1)))
+	return l, statusError(status)
+}
+
+// GetRadialCircles returns the start and end circle of a radial gradient pattern.
+func (self *Pattern) GetRadialCircles() (r Radial, err error) {
+	defer runtime.KeepAlive(self)
+	status := Status(C.cairo_pattern_get_radial_circles(self.pattern,
+		(*C.double)(&r.CX0), (*C.double)(&r.CY0), (*C.double)(&r.Radius0),
+		(*C.double)(&r.CX1), (*C.double)(&r.CY1), (*C.double)(&r.Radius1)))
+	return r, statusError(status)
+}
+
+// GetRGBA returns the color of a solid pattern.
+func (self *Pattern) GetRGBA() (red, green, blue, alpha float64, err error) {
+	defer runtime.KeepAlive(self)
+	status := Status(C.cairo_pattern_get_rgba(self.pattern,
+		(*C.double)(&red), (*C.double)(&green), (*C.double)(&blue), (*C.double)(&alpha)))
+	return red, green, blue, alpha, statusError(status)
+}
+
+// GetSurface returns the surface of a surface pattern.
+// The returned Surface holds a new reference to the cairo surface
+// and has its own default context.
+func (self *Pattern) GetSurface() (*Surface, error) {
+	defer runtime.KeepAlive(self)
+	var s *C.cairo_surface_t
+	if err := statusError(Status(C.cairo_pattern_get_surface(self.pattern, &s))); err != nil {
+		return nil, err
+	}
+	return newSurface(C.cairo_surface_reference(s)), nil
+}
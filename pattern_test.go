@@ -0,0 +1,173 @@
+//go:build !goci
+// +build !goci
+
+package cairo
+
+import (
+	"errors"
+	"image/color"
+	"math"
+	"testing"
+)
+
+func nearlyEqual(a, b, tolerance float64) bool {
+	return math.Abs(a-b) <= tolerance
+}
+
+var colorToRGBATests = []struct {
+	name                    string
+	color                   color.Color
+	red, green, blue, alpha float64
+}{
+	{"opaque", color.NRGBA{R: 0xff, G: 0x80, B: 0x00, A: 0xff}, 1, 0x80 / 255.0, 0, 1},
+	{"opaque gray", color.Gray{Y: 0x33}, 0.2, 0.2, 0.2, 1},
+	{"translucent", color.NRGBA{R: 0xff, G: 0x00, B: 0x33, A: 0x80}, 1, 0, 0.2, 0x80 / 255.0},
+	{"translucent premultiplied", color.RGBA{R: 0x40, G: 0x20, B: 0x00, A: 0x80}, 0.5, 0.25, 0, 0x80 / 255.0},
+	{"zero alpha", color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0x00}, 0, 0, 0, 0},
+	{"transparent", color.Transparent, 0, 0, 0, 0},
+}
+
+func TestColorToRGBA(t *testing.T) {
+	for _, test := range colorToRGBATests {
+		red, green, blue, alpha := colorToRGBA(test.color)
+		if !nearlyEqual(red, test.red, 1e-3) || !nearlyEqual(green, test.green, 1e-3) ||
+			!nearlyEqual(blue, test.blue, 1e-3) || !nearlyEqual(alpha, test.alpha, 1e-9) {
+			t.Errorf("%s: colorToRGBA(%v) is %v, %v, %v, %v, expected %v, %v, %v, %v", test.name, test.color,
+				red, green, blue, alpha, test.red, test.green, test.blue, test.alpha)
+		}
+	}
+}
+
+func TestPatternColor(t *testing.T) {
+	for _, test := range colorToRGBATests {
+		pattern := NewPatternColor(test.color)
+		red, green, blue, alpha, err := pattern.GetRGBA()
+		pattern.Destroy()
+		if err != nil {
+			t.Errorf("%s: GetRGBA() returned %v", test.name, err)
+			continue
+		}
+		if !nearlyEqual(red, test.red, 1e-3) || !nearlyEqual(green, test.green, 1e-3) ||
+			!nearlyEqual(blue, test.blue, 1e-3) || !nearlyEqual(alpha, test.alpha, 1e-3) {
+			t.Errorf("%s: GetRGBA() is %v, %v, %v, %v, expected %v, %v, %v, %v", test.name,
+				red, green, blue, alpha, test.red, test.green, test.blue, test.alpha)
+		}
+	}
+}
+
+func TestPatternColorStops(t *testing.T) {
+	pattern := NewPatternLinear(Linear{X0: 0, Y0: 0, X1: 10, Y1: 0})
+	defer pattern.Destroy()
+	for i, test := range colorToRGBATests {
+		pattern.AddColorStop(float64(i)/float64(len(colorToRGBATests)), test.color)
+	}
+
+	stops, err := pattern.GetColorStops()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stops) != len(colorToRGBATests) {
+		t.Fatalf("%d color stops, expected %d", len(stops), len(colorToRGBATests))
+	}
+	for i, test := range colorToRGBATests {
+		stop := stops[i]
+		if !nearlyEqual(stop.Offset, float64(i)/float64(len(colorToRGBATests)), 1e-6) ||
+			!nearlyEqual(stop.Red, test.red, 1e-3) || !nearlyEqual(stop.Green, test.green, 1e-3) ||
+			!nearlyEqual(stop.Blue, test.blue, 1e-3) || !nearlyEqual(stop.Alpha, test.alpha, 1e-3) {
+			t.Errorf("%s: color stop is %+v", test.name, stop)
+		}
+	}
+
+	solid := NewPatternRGB(1, 0, 0)
+	defer solid.Destroy()
+	if _, err := solid.GetColorStops(); !errors.Is(err, STATUS_PATTERN_TYPE_MISMATCH) {
+		t.Errorf("GetColorStops() of a solid pattern returned %v, expected STATUS_PATTERN_TYPE_MISMATCH", err)
+	}
+}
+
+func TestPatternMesh(t *testing.T) {
+	type corner struct{ red, green, blue, alpha float64 }
+	patches := []struct {
+		build   func(mesh *Pattern)
+		corners [4]Point
+		colors  [4]corner
+	}{
+		{
+			func(mesh *Pattern) {
+				mesh.MeshMoveTo(0, 0)
+				mesh.MeshLineTo(10, 0)
+				mesh.MeshLineTo(10, 10)
+				mesh.MeshLineTo(0, 10)
+				mesh.MeshLineTo(0, 0)
+				mesh.MeshSetCornerColorRGB(0, 1, 0, 0)
+				mesh.MeshSetCornerColorRGB(1, 0, 1, 0)
+				mesh.MeshSetCornerColorRGB(2, 0, 0, 1)
+				mesh.MeshSetCornerColorRGB(3, 1, 1, 0)
+			},
+			[4]Point{{10, 0}, {10, 10}, {0, 10}, {0, 0}},
+			[4]corner{{1, 0, 0, 1}, {0, 1, 0, 1}, {0, 0, 1, 1}, {1, 1, 0, 1}},
+		},
+		{
+			func(mesh *Pattern) {
+				mesh.MeshMoveTo(20, 0)
+				mesh.MeshCurveTo(25, -5, 35, 5, 40, 0)
+				mesh.MeshLineTo(40, 20)
+				mesh.MeshLineTo(20, 20)
+				mesh.MeshSetCornerColorRGBA(0, 0.5, 0.5, 0.5, 0.25)
+				mesh.MeshSetCornerColorRGBA(2, 0, 0, 0, 0.75)
+			},
+			[4]Point{{40, 0}, {40, 20}, {20, 20}, {20, 0}},
+			[4]corner{{0.5, 0.5, 0.5, 0.25}, {0, 0, 0, 0}, {0, 0, 0, 0.75}, {0, 0, 0, 0}},
+		},
+	}
+
+	mesh := NewPatternMesh()
+	defer mesh.Destroy()
+	for _, patch := range patches {
+		mesh.MeshBeginPatch()
+		patch.build(mesh)
+		mesh.MeshEndPatch()
+	}
+	if status := mesh.Status(); status != STATUS_SUCCESS {
+		t.Fatalf("Status() is %s", status)
+	}
+	count, err := mesh.MeshGetPatchCount()
+	if err != nil || count != len(patches) {
+		t.Fatalf("MeshGetPatchCount() is %d, %v, expected %d", count, err, len(patches))
+	}
+
+	for i, patch := range patches {
+		path, err := mesh.MeshGetPath(i)
+		if err != nil {
+			t.Errorf("patch %d: MeshGetPath() returned %v", i, err)
+			continue
+		}
+		if len(path.Segments) != 5 || path.Segments[0].Type != PATH_MOVE_TO {
+			t.Errorf("patch %d: path is %v, expected a move and four sides", i, path.Segments)
+			continue
+		}
+		for side, segment := range path.Segments[1:] {
+			end := segment.Points[len(segment.Points)-1]
+			if segment.Type != PATH_CURVE_TO || !nearlyEqual(end.X, patch.corners[side].X, 0.01) || !nearlyEqual(end.Y, patch.corners[side].Y, 0.01) {
+				t.Errorf("patch %d: side %d is %v, expected a curve to %v", i, side, segment, patch.corners[side])
+			}
+		}
+		for c, expected := range patch.colors {
+			red, green, blue, alpha, err := mesh.MeshGetCornerColorRGBA(i, c)
+			if err != nil {
+				t.Errorf("patch %d: MeshGetCornerColorRGBA(%d) returned %v", i, c, err)
+				continue
+			}
+			if color := (corner{red, green, blue, alpha}); color != expected {
+				t.Errorf("patch %d: corner %d has color %v, expected %v", i, c, color, expected)
+			}
+		}
+	}
+
+	if _, err := mesh.MeshGetPath(len(patches)); !errors.Is(err, STATUS_INVALID_INDEX) {
+		t.Errorf("MeshGetPath() of a missing patch returned %v, expected STATUS_INVALID_INDEX", err)
+	}
+	if _, _, _, _, err := mesh.MeshGetCornerColorRGBA(len(patches), 0); !errors.Is(err, STATUS_INVALID_INDEX) {
+		t.Errorf("MeshGetCornerColorRGBA() of a missing patch returned %v, expected STATUS_INVALID_INDEX", err)
+	}
+}
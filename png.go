@@ -2,105 +2,90 @@ package cairo
 
 // #cgo CFLAGS: -Wall -O2
 // #cgo pkg-config: cairo
-// #include <stdio.h>
-// #include <stdlib.h>
-// #include <string.h>
+// #include <stdint.h>
 // #include <cairo.h>
 //
-// #define GO_CAIRO_VECTOR_INIT_SIZE 64
+// extern cairo_status_t go_cairo_write_func(void *closure, const unsigned char *data, unsigned int length);
+// extern cairo_status_t go_cairo_read_func(void *closure, unsigned char *data, unsigned int length);
 //
-// typedef struct go_cairo_vector {
-// 	char *buf;
-// 	size_t cap;
-// 	size_t len;
-// } go_cairo_vector;
-//
-// void go_cairo_vector_free(go_cairo_vector *v) {
-// 	if (v->buf) {
-// 		free(v->buf);
-// 	}
-// 	free(v);
-// }
-//
-// void go_cairo_vector_init(go_cairo_vector *v) {
-// 	v->len = 0;
-// 	v->cap = 0;
-// 	size_t cap = GO_CAIRO_VECTOR_INIT_SIZE;
-// 	char *buf = malloc(sizeof(char) * cap);
-// 	if (buf) {
-// 		v->buf = buf;
-// 		v->cap = cap;
-// 	}
-// }
-//
-// go_cairo_vector *go_cairo_vector_new() {
-// 	go_cairo_vector *v = malloc(sizeof(go_cairo_vector));
-// 	go_cairo_vector_init(v);
-// 	return v;
-// }
-//
-// static int go_cairo_vector_grow(go_cairo_vector *v, size_t len) {
-// 	int status = 0;
-// 	if (v->cap <= v->len+len) {
-// 		unsigned int cap;
-// 		char* buf;
-// 		if (v->cap == 0) {
-// 			cap = GO_CAIRO_VECTOR_INIT_SIZE + len;
-// 			buf = malloc(sizeof(char) * cap);
-// 		} else {
-// 			cap = (v->cap*2) + len;
-// 			buf = realloc(v->buf, sizeof(char) * cap);
-// 		}
-// 		if (buf) {
-// 			v->buf = buf;
-// 			v->cap = cap;
-// 		} else {
-// 			status = 1;
-// 		}
-// 	}
-// 	return status;
-// }
-//
-// static int go_cairo_vector_append(go_cairo_vector *v, const unsigned char *data, size_t len) {
-// 	int status = go_cairo_vector_grow(v, len);
-// 	if (status == 0) {
-// 		memcpy(&v->buf[v->len], data, len);
-// 		v->len += len;
-// 	}
-// 	return status;
-// }
-//
-// cairo_status_t go_cairo_writer(void *closure, const unsigned char *data, unsigned int length) {
-// 	cairo_status_t status;
-// 	go_cairo_vector *v = (go_cairo_vector*)closure;
-// 	switch (go_cairo_vector_append(v, data, (size_t) length)) {
-// 	case 0:
-// 		status = CAIRO_STATUS_SUCCESS;
-// 		break;
-// 	case 1:
-// 		status = CAIRO_STATUS_NO_MEMORY;
-// 		break;
-// 	default:
-// 		status = CAIRO_STATUS_WRITE_ERROR;
-// 		break;
-// 	}
-// 	return status;
+// static cairo_status_t go_cairo_surface_write_to_png_stream(cairo_surface_t *surface, uintptr_t closure) {
+// 	return cairo_surface_write_to_png_stream(surface, go_cairo_write_func, (void *)closure);
 // }
 //
-// cairo_status_t go_cairo_write_surface_to_vector(cairo_surface_t *surface, go_cairo_vector *vec)
-// {
-// 	cairo_status_t status = cairo_surface_write_to_png_stream(surface, go_cairo_writer, vec);
-// 	cairo_surface_destroy(surface);
-// 	return status;
+// static cairo_surface_t *go_cairo_image_surface_create_from_png_stream(uintptr_t closure) {
+// 	return cairo_image_surface_create_from_png_stream(go_cairo_read_func, (void *)closure);
 // }
 import "C"
 
-import "unsafe"
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"io/fs"
+	"runtime"
+	"runtime/cgo"
+)
+
+// NewSurfaceFromPNGReader creates an image surface from PNG data read from r.
+// If reading from r fails, the returned error matches STATUS_READ_ERROR
+// and the error returned by r with errors.Is.
+func NewSurfaceFromPNGReader(r io.Reader) (*Surface, error) {
+	stream := &readStream{reader: r}
+	handle := cgo.NewHandle(stream)
+	defer handle.Delete()
+
+	s := C.go_cairo_image_surface_create_from_png_stream(C.uintptr_t(handle))
+	if status := Status(C.cairo_surface_status(s)); status != STATUS_SUCCESS {
+		C.cairo_surface_destroy(s)
+		if stream.err != nil {
+			return nil, &callbackError{status: status, err: stream.err}
+		}
+		return nil, statusError(status)
+	}
+	return newSurface(s), nil
+}
+
+// NewSurfaceFromPNGBytes creates an image surface from PNG data.
+func NewSurfaceFromPNGBytes(data []byte) (*Surface, error) {
+	return NewSurfaceFromPNGReader(bytes.NewReader(data))
+}
+
+// NewSurfaceFromPNGFS creates an image surface from the PNG file name in fsys,
+// for example an embed.FS.
+func NewSurfaceFromPNGFS(fsys fs.FS, name string) (*Surface, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return NewSurfaceFromPNGReader(bufio.NewReader(file))
+}
+
+// writePNG streams the PNG encoded surface to w.
+// The returned error is the first error returned by w.
+func (self *Surface) writePNG(w io.Writer) (Status, error) {
+	defer runtime.KeepAlive(self)
+	stream := &writeStream{writer: w}
+	handle := cgo.NewHandle(stream)
+	defer handle.Delete()
+	status := Status(C.go_cairo_surface_write_to_png_stream(self.surface, C.uintptr_t(handle)))
+	return status, stream.err
+}
+
+// WritePNG writes the surface as PNG to w.
+// The data is passed to w in chunks while cairo encodes it,
+// the surface stays usable after the call.
+func (self *Surface) WritePNG(w io.Writer) error {
+	status, err := self.writePNG(w)
+	if err != nil {
+		return &callbackError{status: status, err: err}
+	}
+	return statusError(status)
+}
 
+// WriteToPNGStream returns the surface encoded as PNG.
 func (self *Surface) WriteToPNGStream() ([]byte, Status) {
-	vec := C.go_cairo_vector_new()
-	status := Status(C.go_cairo_write_surface_to_vector(self.surface, vec))
-	buf := C.GoBytes(unsafe.Pointer(vec.buf), C.int(vec.len))
-	C.go_cairo_vector_free(vec)
-	return buf, status
+	var buf bytes.Buffer
+	status, _ := self.writePNG(&buf)
+	return buf.Bytes(), status
 }
@@ -0,0 +1,250 @@
+//go:build !goci
+// +build !goci
+
+package cairo
+
+/*
+#include <stdint.h>
+#include <cairo/cairo.h>
+
+extern cairo_surface_t *goCairoRasterSourceAcquire(uintptr_t source, cairo_rectangle_int_t *extents);
+extern void goCairoRasterSourceRelease(uintptr_t source, cairo_surface_t *surface);
+extern cairo_status_t goCairoRasterSourceCopy(uintptr_t source);
+extern void goCairoRasterSourceFinish(uintptr_t source);
+
+static cairo_surface_t *go_cairo_raster_source_acquire(cairo_pattern_t *pattern, void *data, cairo_surface_t *target, const cairo_rectangle_int_t *extents) {
+	return goCairoRasterSourceAcquire((uintptr_t)data, (cairo_rectangle_int_t *)extents);
+}
+
+static void go_cairo_raster_source_release(cairo_pattern_t *pattern, void *data, cairo_surface_t *surface) {
+	goCairoRasterSourceRelease((uintptr_t)data, surface);
+}
+
+static cairo_status_t go_cairo_raster_source_copy(cairo_pattern_t *pattern, void *data, const cairo_pattern_t *other) {
+	return goCairoRasterSourceCopy((uintptr_t)data);
+}
+
+static void go_cairo_raster_source_finish(cairo_pattern_t *pattern, void *data) {
+	goCairoRasterSourceFinish((uintptr_t)data);
+}
+
+static cairo_pattern_t *go_cairo_pattern_create_raster_source(uintptr_t source, cairo_content_t content, int width, int height) {
+	cairo_pattern_t *pattern = cairo_pattern_create_raster_source((void *)source, content, width, height);
+	cairo_raster_source_pattern_set_acquire(pattern, go_cairo_raster_source_acquire, go_cairo_raster_source_release);
+	cairo_raster_source_pattern_set_copy(pattern, go_cairo_raster_source_copy);
+	cairo_raster_source_pattern_set_finish(pattern, go_cairo_raster_source_finish);
+	return pattern;
+}
+
+// go_cairo_raster_source_data returns the callback data
+// of pattern if it is a raster source created by Go, else 0.
+static uintptr_t go_cairo_raster_source_data(cairo_pattern_t *pattern) {
+	cairo_raster_source_acquire_func_t acquire = NULL;
+	cairo_raster_source_release_func_t release = NULL;
+	if (cairo_pattern_get_type(pattern) != CAIRO_PATTERN_TYPE_RASTER_SOURCE) {
+		return 0;
+	}
+	cairo_raster_source_pattern_get_acquire(pattern, &acquire, &release);
+	if (acquire != go_cairo_raster_source_acquire) {
+		return 0;
+	}
+	return (uintptr_t)cairo_raster_source_pattern_get_callback_data(pattern);
+}
+*/
+import "C"
+
+import (
+	"image"
+	"runtime"
+	"runtime/cgo"
+	"sync"
+)
+
+// RasterSourceAcquireFunc returns a surface with the pixels
+// of a raster source pattern covering at least extents,
+// given in pattern space. Pixel (0, 0) of the surface is at the
+// pattern space origin, unless the device offset of the surface is set.
+type RasterSourceAcquireFunc func(extents image.Rectangle) (*Surface, error)
+
+// RasterSourceReleaseFunc is called when cairo is done
+// with a surface returned by a RasterSourceAcquireFunc.
+type RasterSourceReleaseFunc func(surface *Surface)
+
+// RasterSourceImageFunc returns an image with the pixels
+// of a raster source pattern covering at least extents.
+// The bounds of the image are in pattern space, so the image
+// can either cover just extents or the whole pattern.
+type RasterSourceImageFunc func(extents image.Rectangle) (image.Image, error)
+
+// rasterSource holds the Go callbacks of a raster source pattern.
+// cairo shares the callback data between copies of the pattern
+// and calls finish for every copy, so the cgo handle is deleted
+// after the last copy has been finished.
+type rasterSource struct {
+	acquire RasterSourceAcquireFunc
+	release RasterSourceReleaseFunc
+
+	mutex    sync.Mutex
+	refs     int
+	acquired map[*C.cairo_surface_t][]*Surface
+	// err is the last error returned by acquire
+	err error
+}
+
+// NewPatternRasterSource creates a pattern that gets its pixels from acquire
+// whenever cairo samples it. The pattern has the given content and size,
+// patterns with a size of zero are unbounded.
+// release is called after cairo has finished using an acquired surface
+// and may be nil. If acquire returns an error the painting method
+// using the pattern fails and returns the error wrapped with the status.
+// The callbacks may be called from other threads than the one drawing.
+// If acquire is nil, a pattern with STATUS_NULL_POINTER is returned.
+func NewPatternRasterSource(content Content, width, height int, acquire RasterSourceAcquireFunc, release RasterSourceReleaseFunc) *Pattern {
+	if acquire == nil {
+		return &Pattern{pattern: nilPattern}
+	}
+	source := &rasterSource{
+		acquire:  acquire,
+		release:  release,
+		refs:     1,
+		acquired: make(map[*C.cairo_surface_t][]*Surface),
+	}
+	handle := cgo.NewHandle(source)
+	p := C.go_cairo_pattern_create_raster_source(C.uintptr_t(handle), C.cairo_content_t(content), C.int(width), C.int(height))
+	if C.cairo_pattern_status(p) != C.CAIRO_STATUS_SUCCESS {
+		// Error patterns never call finish
+		handle.Delete()
+	}
+	return newPattern(p)
+}
+
+// NewPatternRasterSourceImage creates a raster source pattern
+// like NewPatternRasterSource, but with a callback returning an image.Image.
+// The image is converted to a surface with a format matching content.
+func NewPatternRasterSourceImage(content Content, width, height int, acquire RasterSourceImageFunc) *Pattern {
+	if acquire == nil {
+		return &Pattern{pattern: nilPattern}
+	}
+	var format Format
+	switch content {
+	case CONTENT_COLOR:
+		format = FORMAT_RGB24
+	case CONTENT_ALPHA:
+		format = FORMAT_A8
+	default:
+		format = FORMAT_ARGB32
+	}
+	return NewPatternRasterSource(content, width, height,
+		func(extents image.Rectangle) (*Surface, error) {
+			img, err := acquire(extents)
+			if err != nil {
+				return nil, err
+			}
+			bounds := img.Bounds()
+			surface, err := NewSurfaceChecked(format, bounds.Dx(), bounds.Dy())
+			if err != nil {
+				return nil, err
+			}
+			if err := surface.SetImage(img); err != nil {
+				surface.Destroy()
+				return nil, err
+			}
+			surface.SetDeviceOffset(float64(-bounds.Min.X), float64(-bounds.Min.Y))
+			return surface, nil
+		},
+		(*Surface).Destroy,
+	)
+}
+
+func (self *rasterSource) acquireSurface(extents image.Rectangle) *C.cairo_surface_t {
+	surface, err := self.acquire(extents)
+	if err != nil || surface == nil {
+		if err != nil {
+			self.mutex.Lock()
+			self.err = err
+			self.mutex.Unlock()
+		}
+		return nil
+	}
+	s := C.cairo_surface_reference(surface.surface)
+	self.mutex.Lock()
+	self.acquired[s] = append(self.acquired[s], surface)
+	self.mutex.Unlock()
+	return s
+}
+
+func (self *rasterSource) releaseSurface(s *C.cairo_surface_t) {
+	var surface *Surface
+	self.mutex.Lock()
+	if surfaces := self.acquired[s]; len(surfaces) > 0 {
+		surface = surfaces[len(surfaces)-1]
+		if len(surfaces) == 1 {
+			delete(self.acquired, s)
+		} else {
+			self.acquired[s] = surfaces[:len(surfaces)-1]
+		}
+	}
+	self.mutex.Unlock()
+	C.cairo_surface_destroy(s)
+	if surface != nil && self.release != nil {
+		self.release(surface)
+	}
+}
+
+func (self *rasterSource) copy() {
+	self.mutex.Lock()
+	self.refs++
+	self.mutex.Unlock()
+}
+
+// finish returns true if the last pattern using self has been finished.
+func (self *rasterSource) finish() bool {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	self.refs--
+	return self.refs == 0
+}
+
+// takeError returns and clears the last error of acquire.
+func (self *rasterSource) takeError() error {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	err := self.err
+	self.err = nil
+	return err
+}
+
+// rasterSourceHandle returns the handle of the callbacks of pattern
+// if it is a raster source created by NewPatternRasterSource, else 0.
+func rasterSourceHandle(pattern *C.cairo_pattern_t) cgo.Handle {
+	return cgo.Handle(C.go_cairo_raster_source_data(pattern))
+}
+
+// rasterSourceError returns and clears the last acquire error
+// of pattern if it is a raster source created by NewPatternRasterSource.
+func rasterSourceError(pattern *C.cairo_pattern_t) error {
+	handle := rasterSourceHandle(pattern)
+	if handle == 0 {
+		return nil
+	}
+	return handle.Value().(*rasterSource).takeError()
+}
+
+// drawError returns the status of the context as error after painting
+// with the source and masks. If the acquire callback of one of the
+// patterns returned an error, it is wrapped with the status.
+func (self *Context) drawError(masks ...*C.cairo_pattern_t) error {
+	defer runtime.KeepAlive(self)
+	// Take the errors in any case, so they don't show up later
+	err := rasterSourceError(C.cairo_get_source(self.context))
+	for _, mask := range masks {
+		if maskErr := rasterSourceError(mask); err == nil {
+			err = maskErr
+		}
+	}
+	status := self.Status()
+	if status == STATUS_SUCCESS || err == nil {
+		return statusError(status)
+	}
+	return &callbackError{status: status, err: err}
+}
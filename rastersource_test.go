@@ -0,0 +1,193 @@
+//go:build !goci
+// +build !goci
+
+package cairo
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestNewPatternRasterSourceNilAcquire(t *testing.T) {
+	pattern := NewPatternRasterSource(CONTENT_COLOR_ALPHA, 4, 4, nil, nil)
+	if status := pattern.Status(); status != STATUS_NULL_POINTER {
+		t.Errorf("Status() is %s, expected STATUS_NULL_POINTER", status)
+	}
+	pattern.Destroy()
+
+	pattern = NewPatternRasterSourceImage(CONTENT_COLOR_ALPHA, 4, 4, nil)
+	if status := pattern.Status(); status != STATUS_NULL_POINTER {
+		t.Errorf("Status() is %s, expected STATUS_NULL_POINTER", status)
+	}
+	pattern.Destroy()
+}
+
+// rasterSourceTest records the calls of the callbacks of a raster source.
+type rasterSourceTest struct {
+	extents  []image.Rectangle
+	acquired []*Surface
+	released []*Surface
+}
+
+func (self *rasterSourceTest) acquireSurface(extents image.Rectangle) (*Surface, error) {
+	self.extents = append(self.extents, extents)
+	surface := NewSurface(FORMAT_ARGB32, 8, 8)
+	surface.SetSourceRGB(1, 0, 0)
+	surface.Paint()
+	self.acquired = append(self.acquired, surface)
+	return surface, nil
+}
+
+func (self *rasterSourceTest) releaseSurface(surface *Surface) {
+	self.released = append(self.released, surface)
+	surface.Destroy()
+}
+
+func (self *rasterSourceTest) acquireImage(extents image.Rectangle) (image.Image, error) {
+	self.extents = append(self.extents, extents)
+	img := image.NewNRGBA(extents)
+	for y := extents.Min.Y; y < extents.Max.Y; y++ {
+		for x := extents.Min.X; x < extents.Max.X; x++ {
+			img.Set(x, y, color.NRGBA{R: 0xff, A: 0xff})
+		}
+	}
+	return img, nil
+}
+
+// paintClipped paints source clipped to clip on a new 8x8 surface.
+func paintClipped(t *testing.T, source *Pattern, clip image.Rectangle) (image.Image, error) {
+	t.Helper()
+	surface := NewSurface(FORMAT_ARGB32, 8, 8)
+	defer surface.Destroy()
+	context := NewContext(surface)
+	defer context.Destroy()
+	context.Rectangle(float64(clip.Min.X), float64(clip.Min.Y), float64(clip.Dx()), float64(clip.Dy()))
+	context.Clip()
+	context.SetSource(source)
+	err := context.Paint()
+	return surface.GetImage(), err
+}
+
+func TestRasterSourceAcquire(t *testing.T) {
+	clip := image.Rect(2, 3, 5, 7)
+	bounds := image.Rect(0, 0, 8, 8)
+	tests := []struct {
+		name   string
+		create func(test *rasterSourceTest) *Pattern
+	}{
+		{"surface", func(test *rasterSourceTest) *Pattern {
+			return NewPatternRasterSource(CONTENT_COLOR_ALPHA, 8, 8, test.acquireSurface, test.releaseSurface)
+		}},
+		{"image", func(test *rasterSourceTest) *Pattern {
+			return NewPatternRasterSourceImage(CONTENT_COLOR_ALPHA, 8, 8, test.acquireImage)
+		}},
+	}
+	for _, test := range tests {
+		var calls rasterSourceTest
+		pattern := test.create(&calls)
+		img, err := paintClipped(t, pattern, clip)
+		pattern.Destroy()
+		if err != nil {
+			t.Errorf("%s: Paint() returned %v", test.name, err)
+			continue
+		}
+
+		if len(calls.extents) == 0 {
+			t.Errorf("%s: acquire was not called", test.name)
+		}
+		for _, extents := range calls.extents {
+			if !clip.In(extents) || !extents.In(bounds) {
+				t.Errorf("%s: acquire extents %v, expected to cover %v within %v", test.name, extents, clip, bounds)
+			}
+		}
+		for y := 0; y < 8; y++ {
+			for x := 0; x < 8; x++ {
+				r, g, b, a := img.At(x, y).RGBA()
+				inside := image.Pt(x, y).In(clip)
+				if inside && (r != 0xffff || g != 0 || b != 0 || a != 0xffff) {
+					t.Errorf("%s: pixel %d, %d is %v, expected red from the raster source", test.name, x, y, img.At(x, y))
+				}
+				if !inside && a != 0 {
+					t.Errorf("%s: pixel %d, %d outside of the clip is %v", test.name, x, y, img.At(x, y))
+				}
+			}
+		}
+
+		if test.name == "surface" {
+			if len(calls.released) != len(calls.acquired) {
+				t.Errorf("%s: %d surfaces released, %d acquired", test.name, len(calls.released), len(calls.acquired))
+			}
+			for i, surface := range calls.released {
+				if surface != calls.acquired[i] {
+					t.Errorf("%s: release was not called with the acquired surface", test.name)
+				}
+			}
+		}
+	}
+}
+
+func TestRasterSourceAcquireError(t *testing.T) {
+	acquireErr := errors.New("no pixels")
+	acquire := func(extents image.Rectangle) (*Surface, error) {
+		return nil, acquireErr
+	}
+
+	surface := NewSurface(FORMAT_ARGB32, 8, 8)
+	defer surface.Destroy()
+	draws := []struct {
+		name string
+		draw func(context *Context, pattern *Pattern) error
+	}{
+		{"Paint", func(context *Context, pattern *Pattern) error {
+			context.SetSource(pattern)
+			return context.Paint()
+		}},
+		{"Mask", func(context *Context, pattern *Pattern) error {
+			context.SetSourceRGB(0, 0, 1)
+			return context.Mask(pattern)
+		}},
+	}
+	for _, test := range draws {
+		pattern := NewPatternRasterSource(CONTENT_COLOR_ALPHA, 8, 8, acquire, nil)
+		context := NewContext(surface)
+		err := test.draw(context, pattern)
+		status := context.Status()
+		if status == STATUS_SUCCESS {
+			t.Errorf("%s: status is STATUS_SUCCESS after acquire failed", test.name)
+		}
+		if !errors.Is(err, acquireErr) || !errors.Is(err, status) {
+			t.Errorf("%s: returned %v, expected %s wrapping %v", test.name, err, status, acquireErr)
+		}
+		context.Destroy()
+		pattern.Destroy()
+	}
+}
+
+func TestRasterSourceFinish(t *testing.T) {
+	var calls rasterSourceTest
+	pattern := NewPatternRasterSource(CONTENT_COLOR_ALPHA, 8, 8, calls.acquireSurface, calls.releaseSurface)
+	handle := rasterSourceHandle(pattern.pattern)
+	if handle == 0 {
+		t.Fatal("pattern has no raster source handle")
+	}
+	if _, err := paintClipped(t, pattern, image.Rect(0, 0, 8, 8)); err != nil {
+		t.Fatal(err)
+	}
+	source := handle.Value().(*rasterSource)
+	pattern.Destroy()
+
+	if len(calls.released) != len(calls.acquired) {
+		t.Errorf("%d surfaces released, %d acquired", len(calls.released), len(calls.acquired))
+	}
+	if len(source.acquired) != 0 {
+		t.Errorf("%d acquired surfaces are still referenced", len(source.acquired))
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("handle of the raster source was not deleted after Destroy")
+		}
+	}()
+	handle.Value()
+}
@@ -0,0 +1,280 @@
+//go:build !goci
+// +build !goci
+
+package cairo
+
+// #include <cairo/cairo.h>
+import "C"
+
+import (
+	"image"
+	"runtime"
+)
+
+// Region is a set of integer aligned rectangles (cairo_region_t).
+// Rectangles are given as image.Rectangle with exclusive Max coordinates.
+// A destroyed Region is empty, its Status is STATUS_NULL_POINTER and
+// methods that modify it or use it return STATUS_NULL_POINTER as error.
+//
+// Unlike Destroy of the other types, Destroy sets the pointer to nil
+// instead of a nil object like nilPattern: cairo has no public way to
+// create a region in an error state, so every method checks for nil.
+type Region struct {
+	region *C.cairo_region_t
+	_      *byte // not tiny allocated, see Pattern
+}
+
+// newRegion wraps r and destroys it when the Region
+// gets garbage collected without being destroyed.
+func newRegion(r *C.cairo_region_t) *Region {
+	region := &Region{region: r}
+	runtime.SetFinalizer(region, (*Region).Destroy)
+	return region
+}
+
+func cairoRectangleInt(rect image.Rectangle) C.cairo_rectangle_int_t {
+	rect = rect.Canon()
+	return C.cairo_rectangle_int_t{
+		x:      C.int(rect.Min.X),
+		y:      C.int(rect.Min.Y),
+		width:  C.int(rect.Dx()),
+		height: C.int(rect.Dy()),
+	}
+}
+
+func imageRectangle(rect *C.cairo_rectangle_int_t) image.Rectangle {
+	return image.Rect(int(rect.x), int(rect.y), int(rect.x+rect.width), int(rect.y+rect.height))
+}
+
+// NewRegion creates an empty region.
+func NewRegion() *Region {
+	return newRegion(C.cairo_region_create())
+}
+
+// NewRegionRectangle creates a region containing rect.
+func NewRegionRectangle(rect image.Rectangle) *Region {
+	crect := cairoRectangleInt(rect)
+	return newRegion(C.cairo_region_create_rectangle(&crect))
+}
+
+// NewRegionRectangles creates a region containing the union of rects.
+func NewRegionRectangles(rects []image.Rectangle) *Region {
+	if len(rects) == 0 {
+		return NewRegion()
+	}
+	crects := make([]C.cairo_rectangle_int_t, len(rects))
+	for i, rect := range rects {
+		crects[i] = cairoRectangleInt(rect)
+	}
+	return newRegion(C.cairo_region_create_rectangles(&crects[0], C.int(len(crects))))
+}
+
+// Copy returns a new region with the same rectangles.
+// The copy of a destroyed region is destroyed too.
+func (self *Region) Copy() *Region {
+	defer runtime.KeepAlive(self)
+	if self.region == nil {
+		return &Region{}
+	}
+	return newRegion(C.cairo_region_copy(self.region))
+}
+
+// Reference returns a new Region for the same cairo_region_t
+// and increases its reference count.
+// Both Regions have to be destroyed.
+// The reference of a destroyed region is destroyed too.
+func (self *Region) Reference() *Region {
+	defer runtime.KeepAlive(self)
+	if self.region == nil {
+		return &Region{}
+	}
+	return newRegion(C.cairo_region_reference(self.region))
+}
+
+// Destroy decreases the reference count of the region.
+// It is safe to call Destroy more than once, after the first call
+// all methods are no-ops and Status returns STATUS_NULL_POINTER.
+func (self *Region) Destroy() {
+	if self.region != nil {
+		C.cairo_region_destroy(self.region)
+		self.region = nil
+	}
+	runtime.SetFinalizer(self, nil)
+}
+
+func (self *Region) Status() Status {
+	defer runtime.KeepAlive(self)
+	if self.region == nil {
+		return STATUS_NULL_POINTER
+	}
+	return Status(C.cairo_region_status(self.region))
+}
+
+func (self *Region) Equal(other *Region) bool {
+	defer runtime.KeepAlive(self)
+	defer runtime.KeepAlive(other)
+	if self.region == nil || other.region == nil {
+		return self.region == other.region
+	}
+	return C.cairo_region_equal(self.region, other.region) != 0
+}
+
+// GetExtents returns the bounding rectangle of the region.
+func (self *Region) GetExtents() image.Rectangle {
+	defer runtime.KeepAlive(self)
+	if self.region == nil {
+		return image.Rectangle{}
+	}
+	var rect C.cairo_rectangle_int_t
+	C.cairo_region_get_extents(self.region, &rect)
+	return imageRectangle(&rect)
+}
+
+func (self *Region) NumRectangles() int {
+	defer runtime.KeepAlive(self)
+	if self.region == nil {
+		return 0
+	}
+	return int(C.cairo_region_num_rectangles(self.region))
+}
+
+// GetRectangle returns the nth rectangle of the region,
+// nth must be smaller than NumRectangles.
+func (self *Region) GetRectangle(nth int) image.Rectangle {
+	defer runtime.KeepAlive(self)
+	if nth < 0 || nth >= self.NumRectangles() {
+		return image.Rectangle{}
+	}
+	var rect C.cairo_rectangle_int_t
+	C.cairo_region_get_rectangle(self.region, C.int(nth), &rect)
+	return imageRectangle(&rect)
+}
+
+// Rectangles returns the non overlapping rectangles
+// that make up the region.
+func (self *Region) Rectangles() []image.Rectangle {
+	defer runtime.KeepAlive(self)
+	rects := make([]image.Rectangle, self.NumRectangles())
+	for i := range rects {
+		var rect C.cairo_rectangle_int_t
+		C.cairo_region_get_rectangle(self.region, C.int(i), &rect)
+		rects[i] = imageRectangle(&rect)
+	}
+	return rects
+}
+
+func (self *Region) IsEmpty() bool {
+	defer runtime.KeepAlive(self)
+	return self.region == nil || C.cairo_region_is_empty(self.region) != 0
+}
+
+func (self *Region) ContainsPoint(x, y int) bool {
+	defer runtime.KeepAlive(self)
+	return self.region != nil && C.cairo_region_contains_point(self.region, C.int(x), C.int(y)) != 0
+}
+
+// ContainsRectangle returns if rect is completely inside (REGION_OVERLAP_IN),
+// outside (REGION_OVERLAP_OUT) or partially inside (REGION_OVERLAP_PART) the region.
+func (self *Region) ContainsRectangle(rect image.Rectangle) RegionOverlap {
+	defer runtime.KeepAlive(self)
+	if self.region == nil {
+		return REGION_OVERLAP_OUT
+	}
+	crect := cairoRectangleInt(rect)
+	return RegionOverlap(C.cairo_region_contains_rectangle(self.region, &crect))
+}
+
+func (self *Region) Translate(dx, dy int) {
+	defer runtime.KeepAlive(self)
+	if self.region != nil {
+		C.cairo_region_translate(self.region, C.int(dx), C.int(dy))
+	}
+}
+
+// regionOp applies op to self and other if both are not destroyed.
+func (self *Region) regionOp(other *Region, op func(dst, other *C.cairo_region_t) C.cairo_status_t) error {
+	defer runtime.KeepAlive(self)
+	defer runtime.KeepAlive(other)
+	if self.region == nil || other.region == nil {
+		return STATUS_NULL_POINTER
+	}
+	return statusError(Status(op(self.region, other.region)))
+}
+
+// rectangleOp applies op to self and rect if self is not destroyed.
+func (self *Region) rectangleOp(rect image.Rectangle, op func(dst *C.cairo_region_t, rect *C.cairo_rectangle_int_t) C.cairo_status_t) error {
+	defer runtime.KeepAlive(self)
+	if self.region == nil {
+		return STATUS_NULL_POINTER
+	}
+	crect := cairoRectangleInt(rect)
+	return statusError(Status(op(self.region, &crect)))
+}
+
+// Union sets the region to the union of the region and other.
+func (self *Region) Union(other *Region) error {
+	return self.regionOp(other, func(dst, other *C.cairo_region_t) C.cairo_status_t {
+		return C.cairo_region_union(dst, other)
+	})
+}
+
+func (self *Region) UnionRectangle(rect image.Rectangle) error {
+	return self.rectangleOp(rect, func(dst *C.cairo_region_t, rect *C.cairo_rectangle_int_t) C.cairo_status_t {
+		return C.cairo_region_union_rectangle(dst, rect)
+	})
+}
+
+// Intersect sets the region to the intersection of the region and other.
+func (self *Region) Intersect(other *Region) error {
+	return self.regionOp(other, func(dst, other *C.cairo_region_t) C.cairo_status_t {
+		return C.cairo_region_intersect(dst, other)
+	})
+}
+
+func (self *Region) IntersectRectangle(rect image.Rectangle) error {
+	return self.rectangleOp(rect, func(dst *C.cairo_region_t, rect *C.cairo_rectangle_int_t) C.cairo_status_t {
+		return C.cairo_region_intersect_rectangle(dst, rect)
+	})
+}
+
+// Subtract removes other from the region.
+func (self *Region) Subtract(other *Region) error {
+	return self.regionOp(other, func(dst, other *C.cairo_region_t) C.cairo_status_t {
+		return C.cairo_region_subtract(dst, other)
+	})
+}
+
+func (self *Region) SubtractRectangle(rect image.Rectangle) error {
+	return self.rectangleOp(rect, func(dst *C.cairo_region_t, rect *C.cairo_rectangle_int_t) C.cairo_status_t {
+		return C.cairo_region_subtract_rectangle(dst, rect)
+	})
+}
+
+// Xor sets the region to the parts contained in either
+// the region or other, but not in both.
+func (self *Region) Xor(other *Region) error {
+	return self.regionOp(other, func(dst, other *C.cairo_region_t) C.cairo_status_t {
+		return C.cairo_region_xor(dst, other)
+	})
+}
+
+func (self *Region) XorRectangle(rect image.Rectangle) error {
+	return self.rectangleOp(rect, func(dst *C.cairo_region_t, rect *C.cairo_rectangle_int_t) C.cairo_status_t {
+		return C.cairo_region_xor_rectangle(dst, rect)
+	})
+}
+
+// ClipRegion intersects the current clip with the rectangles of region,
+// given in user-space coordinates. The current path is replaced.
+// The status of region is returned without changing the clip
+// if region is destroyed or in an error state.
+func (self *Context) ClipRegion(region *Region) error {
+	if err := statusError(region.Status()); err != nil {
+		return err
+	}
+	self.NewPath()
+	for _, rect := range region.Rectangles() {
+		self.Rectangle(float64(rect.Min.X), float64(rect.Min.Y), float64(rect.Dx()), float64(rect.Dy()))
+	}
+	return self.Clip()
+}
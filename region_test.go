@@ -0,0 +1,222 @@
+//go:build !goci
+// +build !goci
+
+package cairo
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"reflect"
+	"testing"
+)
+
+func TestDestroyedRegion(t *testing.T) {
+	region := NewRegionRectangle(image.Rect(0, 0, 2, 2))
+	region.Destroy()
+	region.Destroy()
+
+	if status := region.Status(); status != STATUS_NULL_POINTER {
+		t.Errorf("Status() is %s, expected STATUS_NULL_POINTER", status)
+	}
+	if status := region.Reference().Status(); status != STATUS_NULL_POINTER {
+		t.Errorf("Reference().Status() is %s, expected STATUS_NULL_POINTER", status)
+	}
+	if status := region.Copy().Status(); status != STATUS_NULL_POINTER {
+		t.Errorf("Copy().Status() is %s, expected STATUS_NULL_POINTER", status)
+	}
+	if !region.IsEmpty() || region.NumRectangles() != 0 {
+		t.Error("destroyed region is not empty")
+	}
+	if err := region.UnionRectangle(image.Rect(0, 0, 1, 1)); !errors.Is(err, STATUS_NULL_POINTER) {
+		t.Errorf("UnionRectangle() returned %v, expected STATUS_NULL_POINTER", err)
+	}
+
+	surface := NewSurface(FORMAT_ARGB32, 4, 4)
+	defer surface.Destroy()
+	if err := surface.ClipRegion(region); !errors.Is(err, STATUS_NULL_POINTER) {
+		t.Errorf("ClipRegion() returned %v, expected STATUS_NULL_POINTER", err)
+	}
+}
+
+var regionOpTests = []struct {
+	name      string
+	op        func(region, other *Region) error
+	rectOp    func(region *Region, rect image.Rectangle) error
+	expected  []image.Rectangle
+	extents   image.Rectangle
+	contained image.Point
+}{
+	{
+		"union",
+		(*Region).Union,
+		(*Region).UnionRectangle,
+		[]image.Rectangle{image.Rect(0, 0, 4, 2), image.Rect(0, 2, 6, 4), image.Rect(2, 4, 6, 6)},
+		image.Rect(0, 0, 6, 6),
+		image.Pt(5, 5),
+	},
+	{
+		"intersect",
+		(*Region).Intersect,
+		(*Region).IntersectRectangle,
+		[]image.Rectangle{image.Rect(2, 2, 4, 4)},
+		image.Rect(2, 2, 4, 4),
+		image.Pt(3, 3),
+	},
+	{
+		"subtract",
+		(*Region).Subtract,
+		(*Region).SubtractRectangle,
+		[]image.Rectangle{image.Rect(0, 0, 4, 2), image.Rect(0, 2, 2, 4)},
+		image.Rect(0, 0, 4, 4),
+		image.Pt(1, 3),
+	},
+	{
+		"xor",
+		(*Region).Xor,
+		(*Region).XorRectangle,
+		[]image.Rectangle{image.Rect(0, 0, 4, 2), image.Rect(0, 2, 2, 4), image.Rect(4, 2, 6, 4), image.Rect(2, 4, 6, 6)},
+		image.Rect(0, 0, 6, 6),
+		image.Pt(5, 3),
+	},
+}
+
+func checkRegion(t *testing.T, name string, region *Region, expected []image.Rectangle, extents image.Rectangle) {
+	t.Helper()
+	if rects := region.Rectangles(); !reflect.DeepEqual(rects, expected) {
+		t.Errorf("%s: Rectangles() is %v, expected %v", name, rects, expected)
+	}
+	if n := region.NumRectangles(); n != len(expected) {
+		t.Errorf("%s: NumRectangles() is %d, expected %d", name, n, len(expected))
+	}
+	if e := region.GetExtents(); e != extents {
+		t.Errorf("%s: GetExtents() is %v, expected %v", name, e, extents)
+	}
+}
+
+func TestRegionOps(t *testing.T) {
+	a := image.Rect(0, 0, 4, 4)
+	b := image.Rect(2, 2, 6, 6)
+	for _, test := range regionOpTests {
+		region := NewRegionRectangle(a)
+		other := NewRegionRectangle(b)
+		if err := test.op(region, other); err != nil {
+			t.Errorf("%s: returned %v", test.name, err)
+		}
+		checkRegion(t, test.name, region, test.expected, test.extents)
+		if !region.ContainsPoint(test.contained.X, test.contained.Y) {
+			t.Errorf("%s: ContainsPoint(%d, %d) is false", test.name, test.contained.X, test.contained.Y)
+		}
+		if !region.Equal(NewRegionRectangles(test.expected)) {
+			t.Errorf("%s: not equal to a region of its rectangles", test.name)
+		}
+
+		rectRegion := NewRegionRectangle(a)
+		if err := test.rectOp(rectRegion, b); err != nil {
+			t.Errorf("%s rectangle: returned %v", test.name, err)
+		}
+		checkRegion(t, test.name+" rectangle", rectRegion, test.expected, test.extents)
+
+		other.Destroy()
+		if err := test.op(region, other); !errors.Is(err, STATUS_NULL_POINTER) {
+			t.Errorf("%s with destroyed region: returned %v, expected STATUS_NULL_POINTER", test.name, err)
+		}
+		region.Destroy()
+		rectRegion.Destroy()
+	}
+}
+
+func TestRegionContains(t *testing.T) {
+	region := NewRegionRectangles([]image.Rectangle{image.Rect(0, 0, 4, 4), image.Rect(10, 0, 12, 2)})
+	defer region.Destroy()
+
+	points := []struct {
+		x, y     int
+		expected bool
+	}{
+		{0, 0, true},
+		{3, 3, true},
+		{4, 3, false},
+		{3, 4, false},
+		{-1, 0, false},
+		{11, 1, true},
+		{7, 1, false},
+	}
+	for _, test := range points {
+		if contains := region.ContainsPoint(test.x, test.y); contains != test.expected {
+			t.Errorf("ContainsPoint(%d, %d) is %v, expected %v", test.x, test.y, contains, test.expected)
+		}
+	}
+
+	rects := []struct {
+		rect     image.Rectangle
+		expected RegionOverlap
+	}{
+		{image.Rect(1, 1, 3, 3), REGION_OVERLAP_IN},
+		{image.Rect(0, 0, 4, 4), REGION_OVERLAP_IN},
+		{image.Rectangle{Min: image.Pt(5, 5), Max: image.Pt(3, 3)}, REGION_OVERLAP_PART},
+		{image.Rect(2, 2, 6, 6), REGION_OVERLAP_PART},
+		{image.Rect(0, 0, 12, 2), REGION_OVERLAP_PART},
+		{image.Rect(5, 0, 9, 4), REGION_OVERLAP_OUT},
+		{image.Rect(0, 4, 4, 8), REGION_OVERLAP_OUT},
+	}
+	for _, test := range rects {
+		if overlap := region.ContainsRectangle(test.rect); overlap != test.expected {
+			t.Errorf("ContainsRectangle(%v) is %d, expected %d", test.rect, overlap, test.expected)
+		}
+	}
+}
+
+func TestRegionTranslate(t *testing.T) {
+	tests := []struct {
+		dx, dy   int
+		expected []image.Rectangle
+	}{
+		{0, 0, []image.Rectangle{image.Rect(0, 0, 2, 1), image.Rect(0, 1, 3, 2)}},
+		{5, 2, []image.Rectangle{image.Rect(5, 2, 7, 3), image.Rect(5, 3, 8, 4)}},
+		{-3, -1, []image.Rectangle{image.Rect(-3, -1, -1, 0), image.Rect(-3, 0, 0, 1)}},
+	}
+	for _, test := range tests {
+		region := NewRegionRectangles([]image.Rectangle{image.Rect(0, 0, 2, 2), image.Rect(0, 1, 3, 2)})
+		region.Translate(test.dx, test.dy)
+		checkRegion(t, fmt.Sprintf("Translate(%d, %d)", test.dx, test.dy), region, test.expected, test.expected[0].Union(test.expected[1]))
+		region.Destroy()
+	}
+}
+
+func TestEmptyRegion(t *testing.T) {
+	region := NewRegion()
+	defer region.Destroy()
+	if !region.IsEmpty() {
+		t.Error("NewRegion() is not empty")
+	}
+	checkRegion(t, "NewRegion()", region, []image.Rectangle{}, image.Rectangle{})
+	if overlap := region.ContainsRectangle(image.Rect(0, 0, 1, 1)); overlap != REGION_OVERLAP_OUT {
+		t.Errorf("ContainsRectangle() is %d, expected REGION_OVERLAP_OUT", overlap)
+	}
+}
+
+func TestClipRegion(t *testing.T) {
+	region := NewRegionRectangles([]image.Rectangle{image.Rect(1, 1, 3, 3), image.Rect(5, 0, 8, 2)})
+	defer region.Destroy()
+
+	surface := NewSurface(FORMAT_ARGB32, 8, 4)
+	defer surface.Destroy()
+	if err := surface.ClipRegion(region); err != nil {
+		t.Fatal(err)
+	}
+	surface.SetSourceRGB(0, 0, 1)
+	if err := surface.Paint(); err != nil {
+		t.Fatal(err)
+	}
+
+	img := surface.GetImage()
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 8; x++ {
+			_, _, _, a := img.At(x, y).RGBA()
+			if painted := a == 0xffff; painted != region.ContainsPoint(x, y) {
+				t.Errorf("pixel %d, %d has alpha %#x, painted inside region is expected", x, y, a)
+			}
+		}
+	}
+}
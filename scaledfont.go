@@ -0,0 +1,169 @@
+//go:build !goci
+// +build !goci
+
+package cairo
+
+// #include <cairo/cairo.h>
+// #include <stdlib.h>
+import "C"
+
+import (
+	"runtime"
+	"unsafe"
+)
+
+// newScaledFont wraps f and destroys it when the ScaledFont
+// gets garbage collected without being destroyed.
+func newScaledFont(f *C.cairo_scaled_font_t) *ScaledFont {
+	scaledFont := &ScaledFont{scaledFont: f}
+	runtime.SetFinalizer(scaledFont, (*ScaledFont).Destroy)
+	return scaledFont
+}
+
+// NewScaledFont creates a font of fontFace scaled by fontMatrix
+// to user-space, and by ctm from user-space to device-space.
+// fontOptions may be nil for default options.
+// Check the Status of the returned ScaledFont for errors.
+func NewScaledFont(fontFace *FontFace, fontMatrix, ctm Matrix, fontOptions *FontOptions) *ScaledFont {
+	defer runtime.KeepAlive(fontFace)
+	defer runtime.KeepAlive(fontOptions)
+	var options *C.cairo_font_options_t
+	if fontOptions != nil {
+		options = fontOptions.options
+	} else {
+		options = C.cairo_font_options_create()
+		defer C.cairo_font_options_destroy(options)
+	}
+	return newScaledFont(C.cairo_scaled_font_create(fontFace.face, fontMatrix.cairo_matrix_t(), ctm.cairo_matrix_t(), options))
+}
+
+// NewScaledFontChecked is like NewScaledFont but returns
+// the status of the created font as error.
+func NewScaledFontChecked(fontFace *FontFace, fontMatrix, ctm Matrix, fontOptions *FontOptions) (*ScaledFont, error) {
+	scaledFont := NewScaledFont(fontFace, fontMatrix, ctm, fontOptions)
+	if err := statusError(scaledFont.Status()); err != nil {
+		scaledFont.Destroy()
+		return nil, err
+	}
+	return scaledFont, nil
+}
+
+// Reference returns a new ScaledFont for the same cairo_scaled_font_t
+// and increases its reference count.
+// Both ScaledFonts have to be destroyed.
+func (self *ScaledFont) Reference() *ScaledFont {
+	defer runtime.KeepAlive(self)
+	return newScaledFont(C.cairo_scaled_font_reference(self.scaledFont))
+}
+
+func (self *ScaledFont) GetReferenceCount() int {
+	defer runtime.KeepAlive(self)
+	return int(C.cairo_scaled_font_get_reference_count(self.scaledFont))
+}
+
+// Destroy decreases the reference count of the scaled font.
+// It is safe to call Destroy more than once, after the first call
+// all methods are no-ops and Status returns STATUS_NULL_POINTER.
+func (self *ScaledFont) Destroy() {
+	if self.scaledFont != nilScaledFont {
+		C.cairo_scaled_font_destroy(self.scaledFont)
+		self.scaledFont = nilScaledFont
+	}
+	runtime.SetFinalizer(self, nil)
+}
+
+func (self *ScaledFont) Status() Status {
+	defer runtime.KeepAlive(self)
+	return Status(C.cairo_scaled_font_status(self.scaledFont))
+}
+
+func (self *ScaledFont) GetType() FontType {
+	defer runtime.KeepAlive(self)
+	return FontType(C.cairo_scaled_font_get_type(self.scaledFont))
+}
+
+func (self *ScaledFont) Extents() *FontExtents {
+	defer runtime.KeepAlive(self)
+	var cfe C.cairo_font_extents_t
+	C.cairo_scaled_font_extents(self.scaledFont, &cfe)
+	return newFontExtents(&cfe)
+}
+
+// TextExtents returns the extents of text in user-space
+// as if it would be drawn with ShowText.
+func (self *ScaledFont) TextExtents(text string) *TextExtents {
+	defer runtime.KeepAlive(self)
+	var cte C.cairo_text_extents_t
+	cs := C.CString(text)
+	C.cairo_scaled_font_text_extents(self.scaledFont, cs, &cte)
+	C.free(unsafe.Pointer(cs))
+	return newTextExtents(&cte)
+}
+
+// GlyphExtents returns the extents of glyphs in user-space.
+func (self *ScaledFont) GlyphExtents(glyphs []Glyph) *TextExtents {
+	defer runtime.KeepAlive(self)
+	var cte C.cairo_text_extents_t
+	cglyphs := cairoGlyphs(glyphs)
+	C.cairo_scaled_font_glyph_extents(self.scaledFont, firstGlyph(cglyphs), C.int(len(cglyphs)), &cte)
+	return newTextExtents(&cte)
+}
+
+// TextToGlyphs converts text to glyphs positioned starting at x, y
+// in user-space, and to the clusters mapping the bytes of text to glyphs.
+// The glyphs and clusters can be passed to ShowTextGlyphs.
+func (self *ScaledFont) TextToGlyphs(x, y float64, text string) (glyphs []Glyph, clusters []TextCluster, flags TextClusterFlag, err error) {
+	defer runtime.KeepAlive(self)
+	var (
+		cglyphs     *C.cairo_glyph_t
+		numGlyphs   C.int
+		cclusters   *C.cairo_text_cluster_t
+		numClusters C.int
+		cflags      C.cairo_text_cluster_flags_t
+	)
+	cs := C.CString(text)
+	defer C.free(unsafe.Pointer(cs))
+	status := Status(C.cairo_scaled_font_text_to_glyphs(self.scaledFont, C.double(x), C.double(y),
+		cs, C.int(len(text)), &cglyphs, &numGlyphs, &cclusters, &numClusters, &cflags))
+	defer C.cairo_glyph_free(cglyphs)
+	defer C.cairo_text_cluster_free(cclusters)
+	if err := statusError(status); err != nil {
+		return nil, nil, 0, err
+	}
+	return goGlyphs(cglyphs, int(numGlyphs)), goTextClusters(cclusters, int(numClusters)), TextClusterFlag(cflags), nil
+}
+
+// GetFontFace returns the font face the scaled font was created for.
+func (self *ScaledFont) GetFontFace() *FontFace {
+	defer runtime.KeepAlive(self)
+	return newFontFace(C.cairo_font_face_reference(C.cairo_scaled_font_get_font_face(self.scaledFont)))
+}
+
+// GetFontMatrix returns the matrix from font-space to user-space.
+func (self *ScaledFont) GetFontMatrix() (matrix Matrix) {
+	defer runtime.KeepAlive(self)
+	C.cairo_scaled_font_get_font_matrix(self.scaledFont, (*C.cairo_matrix_t)(unsafe.Pointer(&matrix)))
+	return matrix
+}
+
+// GetCTM returns the matrix from user-space to device-space.
+func (self *ScaledFont) GetCTM() (matrix Matrix) {
+	defer runtime.KeepAlive(self)
+	C.cairo_scaled_font_get_ctm(self.scaledFont, (*C.cairo_matrix_t)(unsafe.Pointer(&matrix)))
+	return matrix
+}
+
+// GetScaleMatrix returns the matrix from font-space to device-space,
+// the product of the font matrix and the CTM.
+func (self *ScaledFont) GetScaleMatrix() (matrix Matrix) {
+	defer runtime.KeepAlive(self)
+	C.cairo_scaled_font_get_scale_matrix(self.scaledFont, (*C.cairo_matrix_t)(unsafe.Pointer(&matrix)))
+	return matrix
+}
+
+func (self *ScaledFont) GetFontOptions() *FontOptions {
+	defer runtime.KeepAlive(self)
+	fontOptions := NewFontOptions()
+	C.cairo_scaled_font_get_font_options(self.scaledFont, fontOptions.options)
+	return fontOptions
+}
@@ -0,0 +1,203 @@
+//go:build !goci
+// +build !goci
+
+package cairo
+
+/*
+#include <stdint.h>
+#include <cairo/cairo-pdf.h>
+#include <cairo/cairo-ps.h>
+#include <cairo/cairo-svg.h>
+
+extern cairo_status_t goCairoWriteFunc(uintptr_t closure, unsigned char *data, unsigned int length);
+extern cairo_status_t goCairoReadFunc(uintptr_t closure, unsigned char *data, unsigned int length);
+extern void goCairoDeleteHandle(uintptr_t handle);
+
+static cairo_user_data_key_t go_cairo_stream_key;
+
+cairo_status_t go_cairo_write_func(void *closure, const unsigned char *data, unsigned int length) {
+	return goCairoWriteFunc((uintptr_t)closure, (unsigned char *)data, length);
+}
+
+cairo_status_t go_cairo_read_func(void *closure, unsigned char *data, unsigned int length) {
+	return goCairoReadFunc((uintptr_t)closure, data, length);
+}
+
+static void go_cairo_delete_handle(void *handle) {
+	goCairoDeleteHandle((uintptr_t)handle);
+}
+
+// go_cairo_surface_attach_stream ties the lifetime of the stream handle
+// to the surface, the handle gets deleted after cairo has finished
+// and destroyed the surface.
+static cairo_status_t go_cairo_surface_attach_stream(cairo_surface_t *surface, uintptr_t closure) {
+	return cairo_surface_set_user_data(surface, &go_cairo_stream_key, (void *)closure, go_cairo_delete_handle);
+}
+
+static cairo_surface_t *go_cairo_pdf_surface_create_for_stream(uintptr_t closure, double width, double height) {
+	return cairo_pdf_surface_create_for_stream(go_cairo_write_func, (void *)closure, width, height);
+}
+
+static cairo_surface_t *go_cairo_ps_surface_create_for_stream(uintptr_t closure, double width, double height) {
+	return cairo_ps_surface_create_for_stream(go_cairo_write_func, (void *)closure, width, height);
+}
+
+static cairo_surface_t *go_cairo_svg_surface_create_for_stream(uintptr_t closure, double width, double height) {
+	return cairo_svg_surface_create_for_stream(go_cairo_write_func, (void *)closure, width, height);
+}
+*/
+import "C"
+
+import (
+	"io"
+	"runtime/cgo"
+)
+
+// writeStream passes the output of cairo's write callbacks to an io.Writer.
+// After the first failed write all further writes fail with STATUS_WRITE_ERROR.
+// status is set if the stream could not be attached to its surface.
+type writeStream struct {
+	writer io.Writer
+	err    error
+	status Status
+}
+
+func (self *writeStream) write(data []byte) Status {
+	if self.err != nil {
+		return STATUS_WRITE_ERROR
+	}
+	if _, err := self.writer.Write(data); err != nil {
+		self.err = err
+		return STATUS_WRITE_ERROR
+	}
+	return STATUS_SUCCESS
+}
+
+// readStream fills the buffers of cairo's read callbacks from an io.Reader.
+// After the first failed read all further reads fail with STATUS_READ_ERROR.
+type readStream struct {
+	reader io.Reader
+	err    error
+}
+
+func (self *readStream) read(data []byte) Status {
+	if self.err != nil {
+		return STATUS_READ_ERROR
+	}
+	if _, err := io.ReadFull(self.reader, data); err != nil {
+		self.err = err
+		return STATUS_READ_ERROR
+	}
+	return STATUS_SUCCESS
+}
+
+// callbackError is returned when a Go callback called by cairo failed,
+// like reading from or writing to a stream.
+// errors.Is matches both the status and the error of the callback.
+type callbackError struct {
+	status Status
+	err    error
+}
+
+func (self *callbackError) Error() string {
+	return self.status.String() + ": " + self.err.Error()
+}
+
+func (self *callbackError) Unwrap() error {
+	return self.err
+}
+
+func (self *callbackError) Is(target error) bool {
+	return target == self.status
+}
+
+// newStreamSurface creates a surface with create writing to w
+// and keeps the stream alive as long as cairo holds the surface.
+// create must also apply all settings of the new surface, because
+// if the stream can't be attached the surface is replaced by a
+// destroyed surface reporting the error.
+func newStreamSurface(w io.Writer, create func(closure C.uintptr_t) *C.cairo_surface_t) *Surface {
+	stream := &writeStream{writer: w}
+	handle := cgo.NewHandle(stream)
+	s := create(C.uintptr_t(handle))
+	if status := Status(C.go_cairo_surface_attach_stream(s, C.uintptr_t(handle))); status != STATUS_SUCCESS {
+		// Error surfaces never call the write function, but a valid surface
+		// without the stream attached would call it with a deleted handle
+		// after this function returned, so destroy it while the handle is valid.
+		C.cairo_surface_destroy(s)
+		handle.Delete()
+		stream.status = status
+		s = nilSurface
+	}
+	surface := newSurface(s)
+	surface.stream = stream
+	return surface
+}
+
+// NewPDFSurfaceForStream creates a PDF surface that writes its output to w.
+// Errors returned by w are reported as STATUS_WRITE_ERROR by Surface.Status
+// and returned by Surface.Finish and Surface.Flush.
+// Call Finish or Destroy when done, a surface that gets garbage collected
+// writes its remaining output to w from the finalizer goroutine.
+func NewPDFSurfaceForStream(w io.Writer, widthInPoints, heightInPoints float64, version PDFVersion) *Surface {
+	return newStreamSurface(w, func(closure C.uintptr_t) *C.cairo_surface_t {
+		s := C.go_cairo_pdf_surface_create_for_stream(closure, C.double(widthInPoints), C.double(heightInPoints))
+		C.cairo_pdf_surface_restrict_to_version(s, C.cairo_pdf_version_t(version))
+		return s
+	})
+}
+
+// NewPDFSurfaceForStreamChecked is like NewPDFSurfaceForStream but returns an error
+// instead of a surface in an error state.
+func NewPDFSurfaceForStreamChecked(w io.Writer, widthInPoints, heightInPoints float64, version PDFVersion) (*Surface, error) {
+	return checkSurface(NewPDFSurfaceForStream(w, widthInPoints, heightInPoints, version))
+}
+
+// NewPSSurfaceForStream creates a PostScript surface that writes its output to w.
+// Errors of w and finishing work as described for NewPDFSurfaceForStream.
+func NewPSSurfaceForStream(w io.Writer, widthInPoints, heightInPoints float64, level PSLevel) *Surface {
+	return newStreamSurface(w, func(closure C.uintptr_t) *C.cairo_surface_t {
+		s := C.go_cairo_ps_surface_create_for_stream(closure, C.double(widthInPoints), C.double(heightInPoints))
+		C.cairo_ps_surface_restrict_to_level(s, C.cairo_ps_level_t(level))
+		return s
+	})
+}
+
+// NewPSSurfaceForStreamChecked is like NewPSSurfaceForStream but returns an error
+// instead of a surface in an error state.
+func NewPSSurfaceForStreamChecked(w io.Writer, widthInPoints, heightInPoints float64, level PSLevel) (*Surface, error) {
+	return checkSurface(NewPSSurfaceForStream(w, widthInPoints, heightInPoints, level))
+}
+
+// NewEPSSurfaceForStream creates an Encapsulated PostScript surface that writes its output to w.
+// Errors of w and finishing work as described for NewPDFSurfaceForStream.
+func NewEPSSurfaceForStream(w io.Writer, widthInPoints, heightInPoints float64, level PSLevel) *Surface {
+	return newStreamSurface(w, func(closure C.uintptr_t) *C.cairo_surface_t {
+		s := C.go_cairo_ps_surface_create_for_stream(closure, C.double(widthInPoints), C.double(heightInPoints))
+		C.cairo_ps_surface_restrict_to_level(s, C.cairo_ps_level_t(level))
+		C.cairo_ps_surface_set_eps(s, 1)
+		return s
+	})
+}
+
+// NewEPSSurfaceForStreamChecked is like NewEPSSurfaceForStream but returns an error
+// instead of a surface in an error state.
+func NewEPSSurfaceForStreamChecked(w io.Writer, widthInPoints, heightInPoints float64, level PSLevel) (*Surface, error) {
+	return checkSurface(NewEPSSurfaceForStream(w, widthInPoints, heightInPoints, level))
+}
+
+// NewSVGSurfaceForStream creates a SVG surface that writes its output to w.
+// Errors of w and finishing work as described for NewPDFSurfaceForStream.
+func NewSVGSurfaceForStream(w io.Writer, widthInPoints, heightInPoints float64, version SVGVersion) *Surface {
+	return newStreamSurface(w, func(closure C.uintptr_t) *C.cairo_surface_t {
+		s := C.go_cairo_svg_surface_create_for_stream(closure, C.double(widthInPoints), C.double(heightInPoints))
+		C.cairo_svg_surface_restrict_to_version(s, C.cairo_svg_version_t(version))
+		return s
+	})
+}
+
+// NewSVGSurfaceForStreamChecked is like NewSVGSurfaceForStream but returns an error
+// instead of a surface in an error state.
+func NewSVGSurfaceForStreamChecked(w io.Writer, widthInPoints, heightInPoints float64, version SVGVersion) (*Surface, error) {
+	return checkSurface(NewSVGSurfaceForStream(w, widthInPoints, heightInPoints, version))
+}
@@ -0,0 +1,91 @@
+//go:build !goci
+// +build !goci
+
+package cairo
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+var streamSurfaceTests = []struct {
+	name   string
+	create func(w io.Writer) (*Surface, error)
+	prefix string
+}{
+	{"PDF", func(w io.Writer) (*Surface, error) {
+		return NewPDFSurfaceForStreamChecked(w, 100, 100, PDF_VERSION_1_5)
+	}, "%PDF-"},
+	{"PS", func(w io.Writer) (*Surface, error) {
+		return NewPSSurfaceForStreamChecked(w, 100, 100, PS_LEVEL_3)
+	}, "%!PS-Adobe"},
+	{"EPS", func(w io.Writer) (*Surface, error) {
+		return NewEPSSurfaceForStreamChecked(w, 100, 100, PS_LEVEL_3)
+	}, "%!PS-Adobe"},
+	{"SVG", func(w io.Writer) (*Surface, error) {
+		return NewSVGSurfaceForStreamChecked(w, 100, 100, SVG_VERSION_1_1)
+	}, "<?xml"},
+}
+
+type failingWriter struct {
+	err error
+}
+
+func (self *failingWriter) Write(p []byte) (int, error) {
+	return 0, self.err
+}
+
+func TestStreamSurfaceOutput(t *testing.T) {
+	for _, test := range streamSurfaceTests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			surface, err := test.create(&buf)
+			if err != nil {
+				t.Skip(err)
+			}
+			surface.SetSourceRGB(1, 0, 0)
+			surface.Rectangle(10, 10, 50, 50)
+			surface.Fill()
+			if err := surface.Finish(); err != nil {
+				t.Errorf("Finish() returned %v", err)
+			}
+			if status := surface.Status(); status != STATUS_SUCCESS {
+				t.Errorf("Status() is %s after Finish()", status)
+			}
+			surface.Destroy()
+			if output := buf.String(); !strings.HasPrefix(output, test.prefix) {
+				t.Errorf("output starts with %.20q, expected %q", output, test.prefix)
+			}
+		})
+	}
+}
+
+func TestStreamSurfaceWriteError(t *testing.T) {
+	writeErr := errors.New("disk full")
+	for _, test := range streamSurfaceTests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			surface, err := test.create(&failingWriter{err: writeErr})
+			if err != nil {
+				t.Skip(err)
+			}
+			surface.SetSourceRGB(1, 0, 0)
+			surface.Paint()
+			err = surface.Finish()
+			if !errors.Is(err, writeErr) || !errors.Is(err, STATUS_WRITE_ERROR) {
+				t.Errorf("Finish() returned %v, expected STATUS_WRITE_ERROR wrapping %v", err, writeErr)
+			}
+			if err := surface.Flush(); !errors.Is(err, writeErr) || !errors.Is(err, STATUS_WRITE_ERROR) {
+				t.Errorf("Flush() returned %v, expected STATUS_WRITE_ERROR wrapping %v", err, writeErr)
+			}
+			if status := surface.Status(); status != STATUS_WRITE_ERROR {
+				t.Errorf("Status() is %s, expected STATUS_WRITE_ERROR", status)
+			}
+			surface.Destroy()
+		})
+	}
+}
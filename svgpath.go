@@ -0,0 +1,485 @@
+//go:build !goci
+// +build !goci
+
+package cairo
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ParseSVGPath parses SVG path data as used in the d attribute
+// of the SVG path element and returns it as Path.
+// Quadratic curves and elliptical arcs are converted to cubic curves.
+func ParseSVGPath(d string) (*Path, error) {
+	p := svgPathParser{data: d, path: &Path{}}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	return p.path, nil
+}
+
+// AppendSVGPath parses SVG path data and appends it
+// to the current path of the context, see ParseSVGPath.
+func (self *Context) AppendSVGPath(d string) error {
+	path, err := ParseSVGPath(d)
+	if err != nil {
+		return err
+	}
+	return self.AppendPath(path)
+}
+
+// SVGPathData returns the current path of the context as SVG path data.
+func (self *Context) SVGPathData() (string, error) {
+	path, err := self.CopyPath()
+	if err != nil {
+		return "", err
+	}
+	return path.SVGPathData(), nil
+}
+
+// SVGPathData returns the path as compact SVG path data
+// with absolute commands and without redundant separators.
+func (self *Path) SVGPathData() string {
+	var w svgPathWriter
+	var start Point
+	closed := false
+	for _, segment := range self.Segments {
+		if len(segment.Points) < segment.Type.numPoints() {
+			continue
+		}
+		switch segment.Type {
+		case PATH_MOVE_TO:
+			p := segment.Points[0]
+			if closed && p == start {
+				// cairo adds a move to the start of the
+				// sub-path after closing it, SVG does the same
+				continue
+			}
+			w.command('M')
+			w.point(p)
+			start = p
+		case PATH_LINE_TO:
+			w.command('L')
+			w.point(segment.Points[0])
+		case PATH_CURVE_TO:
+			w.command('C')
+			w.point(segment.Points[0])
+			w.point(segment.Points[1])
+			w.point(segment.Points[2])
+		case PATH_CLOSE_PATH:
+			w.command('Z')
+		}
+		closed = segment.Type == PATH_CLOSE_PATH
+	}
+	return w.String()
+}
+
+// svgPathWriter writes commands and numbers
+// with as few characters as possible.
+type svgPathWriter struct {
+	strings.Builder
+	last     byte
+	lastNum  string
+	hasPoint bool
+}
+
+func (self *svgPathWriter) command(c byte) {
+	// Repeated commands and lines after a move can be implicit
+	if self.hasPoint && (c == self.last && c != 'M' || c == 'L' && self.last == 'M') {
+		self.last = c
+		return
+	}
+	self.WriteByte(c)
+	self.last = c
+	self.lastNum = ""
+	self.hasPoint = c != 'Z'
+}
+
+func (self *svgPathWriter) point(p Point) {
+	self.number(p.X)
+	self.number(p.Y)
+}
+
+func (self *svgPathWriter) number(v float64) {
+	s := strconv.FormatFloat(math.Round(v*1e6)/1e6, 'f', -1, 64)
+	if s == "-0" {
+		s = "0"
+	}
+	if strings.HasPrefix(s, "0.") {
+		s = s[1:]
+	} else if strings.HasPrefix(s, "-0.") {
+		s = "-" + s[2:]
+	}
+	if self.lastNum != "" && s[0] != '-' &&
+		!(s[0] == '.' && strings.Contains(self.lastNum, ".")) {
+		self.WriteByte(' ')
+	}
+	self.WriteString(s)
+	self.lastNum = s
+}
+
+type svgPathParser struct {
+	data string
+	pos  int
+	path *Path
+
+	current Point
+	start   Point
+	// reflected control point for S and T
+	control     Point
+	lastCommand byte
+}
+
+func (self *svgPathParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("cairo.ParseSVGPath(): "+format+" at offset %d", append(args, self.pos)...)
+}
+
+func (self *svgPathParser) skipSpace() {
+	for self.pos < len(self.data) {
+		switch self.data[self.pos] {
+		case ' ', '\t', '\n', '\r', '\f':
+			self.pos++
+		default:
+			return
+		}
+	}
+}
+
+// skipSeparator skips white space and at most one comma.
+func (self *svgPathParser) skipSeparator() {
+	self.skipSpace()
+	if self.pos < len(self.data) && self.data[self.pos] == ',' {
+		self.pos++
+		self.skipSpace()
+	}
+}
+
+// hasNumber reports if the next token is a number.
+func (self *svgPathParser) hasNumber() bool {
+	self.skipSeparator()
+	if self.pos >= len(self.data) {
+		return false
+	}
+	c := self.data[self.pos]
+	return c == '+' || c == '-' || c == '.' || c >= '0' && c <= '9'
+}
+
+func (self *svgPathParser) number() (float64, error) {
+	self.skipSeparator()
+	start := self.pos
+	i := self.pos
+	if i < len(self.data) && (self.data[i] == '+' || self.data[i] == '-') {
+		i++
+	}
+	digits := 0
+	for ; i < len(self.data) && self.data[i] >= '0' && self.data[i] <= '9'; i++ {
+		digits++
+	}
+	if i < len(self.data) && self.data[i] == '.' {
+		i++
+		for ; i < len(self.data) && self.data[i] >= '0' && self.data[i] <= '9'; i++ {
+			digits++
+		}
+	}
+	if digits == 0 {
+		return 0, self.errorf("expected number")
+	}
+	if i < len(self.data) && (self.data[i] == 'e' || self.data[i] == 'E') {
+		j := i + 1
+		if j < len(self.data) && (self.data[j] == '+' || self.data[j] == '-') {
+			j++
+		}
+		if j < len(self.data) && self.data[j] >= '0' && self.data[j] <= '9' {
+			for j < len(self.data) && self.data[j] >= '0' && self.data[j] <= '9' {
+				j++
+			}
+			i = j
+		}
+	}
+	v, err := strconv.ParseFloat(self.data[start:i], 64)
+	if err != nil {
+		return 0, self.errorf("invalid number %q", self.data[start:i])
+	}
+	self.pos = i
+	return v, nil
+}
+
+// flag parses an arc flag, which may be written without separator.
+func (self *svgPathParser) flag() (bool, error) {
+	self.skipSeparator()
+	if self.pos < len(self.data) {
+		switch self.data[self.pos] {
+		case '0':
+			self.pos++
+			return false, nil
+		case '1':
+			self.pos++
+			return true, nil
+		}
+	}
+	return false, self.errorf("expected flag")
+}
+
+func (self *svgPathParser) numbers(values ...*float64) error {
+	for _, v := range values {
+		var err error
+		if *v, err = self.number(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (self *svgPathParser) parse() error {
+	self.skipSpace()
+	for self.pos < len(self.data) {
+		c := self.data[self.pos]
+		if !strings.ContainsRune("MmLlHhVvCcSsQqTtAaZz", rune(c)) {
+			return self.errorf("invalid command %q", c)
+		}
+		if self.lastCommand == 0 && c != 'M' && c != 'm' {
+			return self.errorf("path data must start with a move")
+		}
+		self.pos++
+		if err := self.command(c); err != nil {
+			return err
+		}
+		// Further coordinates repeat the command,
+		// after a move they are implicit lines
+		for c != 'Z' && c != 'z' && self.hasNumber() {
+			switch c {
+			case 'M':
+				c = 'L'
+			case 'm':
+				c = 'l'
+			}
+			if err := self.command(c); err != nil {
+				return err
+			}
+		}
+		self.skipSpace()
+	}
+	return nil
+}
+
+// command parses the arguments of one command and adds its segments.
+func (self *svgPathParser) command(c byte) error {
+	relative := c >= 'a' && c <= 'z'
+	offset := Point{}
+	if relative {
+		offset = self.current
+	}
+	lastCommand := self.lastCommand
+	self.lastCommand = c
+
+	if c != 'M' && c != 'm' && c != 'Z' && c != 'z' && len(self.path.Segments) > 0 &&
+		self.path.Segments[len(self.path.Segments)-1].Type == PATH_CLOSE_PATH {
+		// Drawing after closing starts a new sub-path at the start point
+		self.path.MoveTo(self.current.X, self.current.Y)
+	}
+
+	switch c {
+	case 'M', 'm':
+		var x, y float64
+		if err := self.numbers(&x, &y); err != nil {
+			return err
+		}
+		self.current = Point{offset.X + x, offset.Y + y}
+		self.start = self.current
+		self.path.MoveTo(self.current.X, self.current.Y)
+
+	case 'L', 'l':
+		var x, y float64
+		if err := self.numbers(&x, &y); err != nil {
+			return err
+		}
+		self.lineTo(Point{offset.X + x, offset.Y + y})
+
+	case 'H', 'h':
+		x, err := self.number()
+		if err != nil {
+			return err
+		}
+		self.lineTo(Point{offset.X + x, self.current.Y})
+
+	case 'V', 'v':
+		y, err := self.number()
+		if err != nil {
+			return err
+		}
+		self.lineTo(Point{self.current.X, offset.Y + y})
+
+	case 'C', 'c':
+		var x1, y1, x2, y2, x, y float64
+		if err := self.numbers(&x1, &y1, &x2, &y2, &x, &y); err != nil {
+			return err
+		}
+		self.curveTo(
+			Point{offset.X + x1, offset.Y + y1},
+			Point{offset.X + x2, offset.Y + y2},
+			Point{offset.X + x, offset.Y + y},
+		)
+
+	case 'S', 's':
+		var x2, y2, x, y float64
+		if err := self.numbers(&x2, &y2, &x, &y); err != nil {
+			return err
+		}
+		c1 := self.current
+		if strings.IndexByte("CcSs", lastCommand) >= 0 {
+			c1 = self.reflectedControl()
+		}
+		self.curveTo(c1,
+			Point{offset.X + x2, offset.Y + y2},
+			Point{offset.X + x, offset.Y + y},
+		)
+
+	case 'Q', 'q':
+		var x1, y1, x, y float64
+		if err := self.numbers(&x1, &y1, &x, &y); err != nil {
+			return err
+		}
+		self.quadTo(Point{offset.X + x1, offset.Y + y1}, Point{offset.X + x, offset.Y + y})
+
+	case 'T', 't':
+		var x, y float64
+		if err := self.numbers(&x, &y); err != nil {
+			return err
+		}
+		q := self.current
+		if strings.IndexByte("QqTt", lastCommand) >= 0 {
+			q = self.reflectedControl()
+		}
+		self.quadTo(q, Point{offset.X + x, offset.Y + y})
+
+	case 'A', 'a':
+		var rx, ry, rotation, x, y float64
+		if err := self.numbers(&rx, &ry, &rotation); err != nil {
+			return err
+		}
+		largeArc, err := self.flag()
+		if err != nil {
+			return err
+		}
+		sweep, err := self.flag()
+		if err != nil {
+			return err
+		}
+		if err := self.numbers(&x, &y); err != nil {
+			return err
+		}
+		self.arcTo(rx, ry, rotation, largeArc, sweep, Point{offset.X + x, offset.Y + y})
+
+	case 'Z', 'z':
+		self.path.ClosePath()
+		self.current = self.start
+	}
+	return nil
+}
+
+func (self *svgPathParser) reflectedControl() Point {
+	return Point{2*self.current.X - self.control.X, 2*self.current.Y - self.control.Y}
+}
+
+func (self *svgPathParser) lineTo(p Point) {
+	self.path.LineTo(p.X, p.Y)
+	self.current = p
+}
+
+func (self *svgPathParser) curveTo(c1, c2, p Point) {
+	self.path.CurveTo(c1.X, c1.Y, c2.X, c2.Y, p.X, p.Y)
+	self.control = c2
+	self.current = p
+}
+
+// quadTo adds the quadratic curve with control point q as cubic curve.
+func (self *svgPathParser) quadTo(q, p Point) {
+	p0 := self.current
+	self.path.CurveTo(
+		p0.X+2.0/3.0*(q.X-p0.X), p0.Y+2.0/3.0*(q.Y-p0.Y),
+		p.X+2.0/3.0*(q.X-p.X), p.Y+2.0/3.0*(q.Y-p.Y),
+		p.X, p.Y,
+	)
+	self.control = q
+	self.current = p
+}
+
+// arcTo adds an elliptical arc in SVG endpoint parameterization
+// as cubic curves with at most 90° each, see
+// https://www.w3.org/TR/SVG11/implnote.html#ArcImplementationNotes
+func (self *svgPathParser) arcTo(rx, ry, rotation float64, largeArc, sweep bool, p Point) {
+	p0 := self.current
+	if p0 == p {
+		return
+	}
+	rx, ry = math.Abs(rx), math.Abs(ry)
+	if rx == 0 || ry == 0 {
+		self.lineTo(p)
+		return
+	}
+
+	phi := rotation * math.Pi / 180
+	sinPhi, cosPhi := math.Sincos(phi)
+	dx, dy := (p0.X-p.X)/2, (p0.Y-p.Y)/2
+	x1 := cosPhi*dx + sinPhi*dy
+	y1 := -sinPhi*dx + cosPhi*dy
+
+	// Scale up radii that are too small to reach the end point
+	if lambda := x1*x1/(rx*rx) + y1*y1/(ry*ry); lambda > 1 {
+		s := math.Sqrt(lambda)
+		rx, ry = rx*s, ry*s
+	}
+
+	num := rx*rx*ry*ry - rx*rx*y1*y1 - ry*ry*x1*x1
+	den := rx*rx*y1*y1 + ry*ry*x1*x1
+	coef := math.Sqrt(math.Max(0, num/den))
+	if largeArc == sweep {
+		coef = -coef
+	}
+	cx1 := coef * rx * y1 / ry
+	cy1 := -coef * ry * x1 / rx
+	cx := cosPhi*cx1 - sinPhi*cy1 + (p0.X+p.X)/2
+	cy := sinPhi*cx1 + cosPhi*cy1 + (p0.Y+p.Y)/2
+
+	theta1 := math.Atan2((y1-cy1)/ry, (x1-cx1)/rx)
+	dtheta := math.Atan2((-y1-cy1)/ry, (-x1-cx1)/rx) - theta1
+	if sweep && dtheta < 0 {
+		dtheta += 2 * math.Pi
+	} else if !sweep && dtheta > 0 {
+		dtheta -= 2 * math.Pi
+	}
+
+	segments := int(math.Ceil(math.Abs(dtheta) / (math.Pi / 2)))
+	delta := dtheta / float64(segments)
+	// Length of the control point tangents for a segment of delta radians
+	t := 4.0 / 3.0 * math.Tan(delta/4)
+
+	ellipsePoint := func(theta float64) (x, y, dxdt, dydt float64) {
+		sin, cos := math.Sincos(theta)
+		x = cx + rx*cos*cosPhi - ry*sin*sinPhi
+		y = cy + rx*cos*sinPhi + ry*sin*cosPhi
+		dxdt = -rx*sin*cosPhi - ry*cos*sinPhi
+		dydt = -rx*sin*sinPhi + ry*cos*cosPhi
+		return x, y, dxdt, dydt
+	}
+
+	theta := theta1
+	x0, y0, dx0, dy0 := ellipsePoint(theta)
+	for i := 0; i < segments; i++ {
+		theta += delta
+		x3, y3, dx3, dy3 := ellipsePoint(theta)
+		end := Point{x3, y3}
+		if i == segments-1 {
+			end = p
+		}
+		self.curveTo(
+			Point{x0 + t*dx0, y0 + t*dy0},
+			Point{x3 - t*dx3, y3 - t*dy3},
+			end,
+		)
+		x0, y0, dx0, dy0 = x3, y3, dx3, dy3
+	}
+}
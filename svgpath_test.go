@@ -0,0 +1,263 @@
+//go:build !goci
+// +build !goci
+
+package cairo
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// kappa is the control point distance of a cubic curve
+// approximating a quarter circle of radius 1.
+const kappa = 0.5522847498307936
+
+func expectPath(build func(p *Path)) *Path {
+	path := &Path{}
+	build(path)
+	return path
+}
+
+func pathsEqual(a, b *Path) bool {
+	if len(a.Segments) != len(b.Segments) {
+		return false
+	}
+	for i, segment := range a.Segments {
+		other := b.Segments[i]
+		if segment.Type != other.Type || len(segment.Points) != len(other.Points) {
+			return false
+		}
+		for j, p := range segment.Points {
+			if math.Abs(p.X-other.Points[j].X) > 1e-6 || math.Abs(p.Y-other.Points[j].Y) > 1e-6 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+var svgPathTests = []struct {
+	name string
+	data string
+	path *Path
+}{
+	{"empty", "", &Path{}},
+	{"absolute", "M10 20L30 40", expectPath(func(p *Path) {
+		p.MoveTo(10, 20)
+		p.LineTo(30, 40)
+	})},
+	{"relative", "m10 20l30 40", expectPath(func(p *Path) {
+		p.MoveTo(10, 20)
+		p.LineTo(40, 60)
+	})},
+	{"horizontal and vertical", "M10 20H30V40h-5v-5Z", expectPath(func(p *Path) {
+		p.MoveTo(10, 20)
+		p.LineTo(30, 20)
+		p.LineTo(30, 40)
+		p.LineTo(25, 40)
+		p.LineTo(25, 35)
+		p.ClosePath()
+	})},
+	{"implicit lines after move", "m1 2 3 4 5 6", expectPath(func(p *Path) {
+		p.MoveTo(1, 2)
+		p.LineTo(4, 6)
+		p.LineTo(9, 12)
+	})},
+	{"implicit absolute lines after move", "M1 2 3 4", expectPath(func(p *Path) {
+		p.MoveTo(1, 2)
+		p.LineTo(3, 4)
+	})},
+	{"repeated curves", "M0 0C1 1 2 2 3 3 4 4 5 5 6 6", expectPath(func(p *Path) {
+		p.MoveTo(0, 0)
+		p.CurveTo(1, 1, 2, 2, 3, 3)
+		p.CurveTo(4, 4, 5, 5, 6, 6)
+	})},
+	{"repeated relative curves", "M1 1c1 0 1 1 2 2 1 0 1 1 2 2", expectPath(func(p *Path) {
+		p.MoveTo(1, 1)
+		p.CurveTo(2, 1, 2, 2, 3, 3)
+		p.CurveTo(4, 3, 4, 4, 5, 5)
+	})},
+	{"draw after close", "M10 10l5 0zl0 5", expectPath(func(p *Path) {
+		p.MoveTo(10, 10)
+		p.LineTo(15, 10)
+		p.ClosePath()
+		p.MoveTo(10, 10)
+		p.LineTo(10, 15)
+	})},
+	{"move after close", "M10 10l5 0zm1 1l1 0", expectPath(func(p *Path) {
+		p.MoveTo(10, 10)
+		p.LineTo(15, 10)
+		p.ClosePath()
+		p.MoveTo(11, 11)
+		p.LineTo(12, 11)
+	})},
+	{"smooth cubic", "M0 0C0 10 10 10 10 0S20 -10 20 0", expectPath(func(p *Path) {
+		p.MoveTo(0, 0)
+		p.CurveTo(0, 10, 10, 10, 10, 0)
+		p.CurveTo(10, -10, 20, -10, 20, 0)
+	})},
+	{"relative smooth cubic", "M0 0c0 10 10 10 10 0s10 -10 10 0", expectPath(func(p *Path) {
+		p.MoveTo(0, 0)
+		p.CurveTo(0, 10, 10, 10, 10, 0)
+		p.CurveTo(10, -10, 20, -10, 20, 0)
+	})},
+	{"smooth cubic without previous cubic", "M0 0L5 5S10 10 20 0", expectPath(func(p *Path) {
+		p.MoveTo(0, 0)
+		p.LineTo(5, 5)
+		p.CurveTo(5, 5, 10, 10, 20, 0)
+	})},
+	{"quadratic", "M0 0Q3 3 6 0", expectPath(func(p *Path) {
+		p.MoveTo(0, 0)
+		p.CurveTo(2, 2, 4, 2, 6, 0)
+	})},
+	{"smooth quadratic", "M0 0Q3 3 6 0T12 0", expectPath(func(p *Path) {
+		p.MoveTo(0, 0)
+		p.CurveTo(2, 2, 4, 2, 6, 0)
+		p.CurveTo(8, -2, 10, -2, 12, 0)
+	})},
+	{"relative smooth quadratic", "M0 0q3 3 6 0t6 0", expectPath(func(p *Path) {
+		p.MoveTo(0, 0)
+		p.CurveTo(2, 2, 4, 2, 6, 0)
+		p.CurveTo(8, -2, 10, -2, 12, 0)
+	})},
+	{"smooth quadratic without previous quadratic", "M0 0L6 0T12 0", expectPath(func(p *Path) {
+		p.MoveTo(0, 0)
+		p.LineTo(6, 0)
+		p.CurveTo(6, 0, 8, 0, 12, 0)
+	})},
+	{"half circle arc", "M0 0A5 5 0 0 1 10 0", expectPath(func(p *Path) {
+		p.MoveTo(0, 0)
+		p.CurveTo(0, -5*kappa, 5-5*kappa, -5, 5, -5)
+		p.CurveTo(5+5*kappa, -5, 10, -5*kappa, 10, 0)
+	})},
+	{"half circle arc without sweep", "M0 0A5 5 0 0 0 10 0", expectPath(func(p *Path) {
+		p.MoveTo(0, 0)
+		p.CurveTo(0, 5*kappa, 5-5*kappa, 5, 5, 5)
+		p.CurveTo(5+5*kappa, 5, 10, 5*kappa, 10, 0)
+	})},
+	{"relative arc with compact flags", "M0 0a5 5 0 0110 0", expectPath(func(p *Path) {
+		p.MoveTo(0, 0)
+		p.CurveTo(0, -5*kappa, 5-5*kappa, -5, 5, -5)
+		p.CurveTo(5+5*kappa, -5, 10, -5*kappa, 10, 0)
+	})},
+	{"arc with too small radii", "M0 0A1 1 0 0 1 10 0", expectPath(func(p *Path) {
+		p.MoveTo(0, 0)
+		p.CurveTo(0, -5*kappa, 5-5*kappa, -5, 5, -5)
+		p.CurveTo(5+5*kappa, -5, 10, -5*kappa, 10, 0)
+	})},
+	{"arc with negative radii", "M0 0A-5 -5 0 0 1 10 0", expectPath(func(p *Path) {
+		p.MoveTo(0, 0)
+		p.CurveTo(0, -5*kappa, 5-5*kappa, -5, 5, -5)
+		p.CurveTo(5+5*kappa, -5, 10, -5*kappa, 10, 0)
+	})},
+	{"small arc", "M0 0A5 5 0 0 1 5 5", expectPath(func(p *Path) {
+		p.MoveTo(0, 0)
+		p.CurveTo(5*kappa, 0, 5, 5-5*kappa, 5, 5)
+	})},
+	{"large arc", "M0 0A5 5 0 1 0 5 5", expectPath(func(p *Path) {
+		p.MoveTo(0, 0)
+		p.CurveTo(-5*kappa, 0, -5, 5-5*kappa, -5, 5)
+		p.CurveTo(-5, 5+5*kappa, -5*kappa, 10, 0, 10)
+		p.CurveTo(5*kappa, 10, 5, 5+5*kappa, 5, 5)
+	})},
+	{"arc with zero radius", "M0 0A0 5 0 0 1 10 0", expectPath(func(p *Path) {
+		p.MoveTo(0, 0)
+		p.LineTo(10, 0)
+	})},
+	{"arc to current point", "M0 0A5 5 0 0 1 0 0", expectPath(func(p *Path) {
+		p.MoveTo(0, 0)
+	})},
+	{"numbers without separators", "M1.5.5L-1-2", expectPath(func(p *Path) {
+		p.MoveTo(1.5, 0.5)
+		p.LineTo(-1, -2)
+	})},
+	{"exponents", "M1e-3 2E2L1e+1,-.5e1", expectPath(func(p *Path) {
+		p.MoveTo(0.001, 200)
+		p.LineTo(10, -5)
+	})},
+	{"signs and commas", " M +1 , .5\n\tl-.5-.5 ", expectPath(func(p *Path) {
+		p.MoveTo(1, 0.5)
+		p.LineTo(0.5, 0)
+	})},
+}
+
+func TestParseSVGPath(t *testing.T) {
+	for _, test := range svgPathTests {
+		path, err := ParseSVGPath(test.data)
+		if err != nil {
+			t.Errorf("%s: %q returned error %v", test.name, test.data, err)
+			continue
+		}
+		if !pathsEqual(path, test.path) {
+			t.Errorf("%s: %q parsed as %v, expected %v", test.name, test.data, path.Segments, test.path.Segments)
+		}
+	}
+}
+
+func TestParseSVGPathErrors(t *testing.T) {
+	tests := []struct {
+		data string
+		err  string
+	}{
+		{"L1 1", "must start with a move"},
+		{"M1", "expected number"},
+		{"M1 2L", "expected number"},
+		{"M1 2L3", "expected number"},
+		{"M1,,2", "expected number"},
+		{"M--1 2", "expected number"},
+		{"M.e1 2", "expected number"},
+		{"M1 2X3 4", "invalid command"},
+		{"M1 2 L3 4 #", "invalid command"},
+		{"M0 0A5 5 0 2 1 10 0", "expected flag"},
+		{"M0 0A5 5 0 1", "expected flag"},
+		{"M0 0C1 2 3 4 5", "expected number"},
+	}
+	for _, test := range tests {
+		path, err := ParseSVGPath(test.data)
+		if err == nil {
+			t.Errorf("%q: expected error, got %v", test.data, path.Segments)
+			continue
+		}
+		if !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%q: error %q does not contain %q", test.data, err, test.err)
+		}
+	}
+}
+
+func TestSVGPathData(t *testing.T) {
+	tests := []struct {
+		data     string
+		expected string
+	}{
+		{"M10 20L30 40L50 60", "M10 20 30 40 50 60"},
+		{"M0.5 -0.5L-0.25 0.75", "M.5-.5-.25.75"},
+		{"M0 0L1 0L1 1ZM2 2L3 3", "M0 0 1 0 1 1ZM2 2 3 3"},
+		{"M0 0L1 0ZL0 1", "M0 0 1 0ZL0 1"},
+		{"M0 0C1 2 3 4 5 6C7 8 9 10 11 12", "M0 0C1 2 3 4 5 6 7 8 9 10 11 12"},
+		{"M1e-9 0.1234567", "M0 .123457"},
+	}
+	for _, test := range tests {
+		path, err := ParseSVGPath(test.data)
+		if err != nil {
+			t.Fatalf("%q: %v", test.data, err)
+		}
+		if data := path.SVGPathData(); data != test.expected {
+			t.Errorf("%q: SVGPathData() is %q, expected %q", test.data, data, test.expected)
+		}
+	}
+}
+
+func TestSVGPathDataRoundTrip(t *testing.T) {
+	for _, test := range svgPathTests {
+		data := test.path.SVGPathData()
+		path, err := ParseSVGPath(data)
+		if err != nil {
+			t.Errorf("%s: parsing %q returned error %v", test.name, data, err)
+			continue
+		}
+		if !pathsEqual(path, test.path) {
+			t.Errorf("%s: %q parsed as %v, expected %v", test.name, data, path.Segments, test.path.Segments)
+		}
+	}
+}
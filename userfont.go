@@ -0,0 +1,205 @@
+//go:build !goci
+// +build !goci
+
+package cairo
+
+/*
+#include <stdint.h>
+#include <cairo/cairo.h>
+
+extern cairo_status_t goCairoUserFontInit(uintptr_t funcs, cairo_scaled_font_t *scaled_font, cairo_t *cr, cairo_font_extents_t *extents);
+extern cairo_status_t goCairoUserFontRenderGlyph(uintptr_t funcs, cairo_scaled_font_t *scaled_font, unsigned long glyph, cairo_t *cr, cairo_text_extents_t *extents);
+extern cairo_status_t goCairoUserFontUnicodeToGlyph(uintptr_t funcs, cairo_scaled_font_t *scaled_font, unsigned long unicode, unsigned long *glyph_index);
+extern cairo_status_t goCairoUserFontTextToGlyphs(uintptr_t funcs, cairo_scaled_font_t *scaled_font, char *utf8, int utf8_len, cairo_glyph_t **glyphs, int *num_glyphs, cairo_text_cluster_t **clusters, int *num_clusters, cairo_text_cluster_flags_t *cluster_flags);
+extern void goCairoDeleteHandle(uintptr_t handle);
+
+static cairo_user_data_key_t go_cairo_user_font_key;
+
+static void go_cairo_user_font_delete_handle(void *handle) {
+	goCairoDeleteHandle((uintptr_t)handle);
+}
+
+// go_cairo_user_font_funcs returns the handle of the Go callbacks
+// stored as user data of the font face of scaled_font.
+static uintptr_t go_cairo_user_font_funcs(cairo_scaled_font_t *scaled_font) {
+	cairo_font_face_t *font_face = cairo_scaled_font_get_font_face(scaled_font);
+	return (uintptr_t)cairo_font_face_get_user_data(font_face, &go_cairo_user_font_key);
+}
+
+static cairo_status_t go_cairo_user_font_init(cairo_scaled_font_t *scaled_font, cairo_t *cr, cairo_font_extents_t *extents) {
+	return goCairoUserFontInit(go_cairo_user_font_funcs(scaled_font), scaled_font, cr, extents);
+}
+
+static cairo_status_t go_cairo_user_font_render_glyph(cairo_scaled_font_t *scaled_font, unsigned long glyph, cairo_t *cr, cairo_text_extents_t *extents) {
+	return goCairoUserFontRenderGlyph(go_cairo_user_font_funcs(scaled_font), scaled_font, glyph, cr, extents);
+}
+
+static cairo_status_t go_cairo_user_font_unicode_to_glyph(cairo_scaled_font_t *scaled_font, unsigned long unicode, unsigned long *glyph_index) {
+	return goCairoUserFontUnicodeToGlyph(go_cairo_user_font_funcs(scaled_font), scaled_font, unicode, glyph_index);
+}
+
+static cairo_status_t go_cairo_user_font_text_to_glyphs(cairo_scaled_font_t *scaled_font, const char *utf8, int utf8_len, cairo_glyph_t **glyphs, int *num_glyphs, cairo_text_cluster_t **clusters, int *num_clusters, cairo_text_cluster_flags_t *cluster_flags) {
+	return goCairoUserFontTextToGlyphs(go_cairo_user_font_funcs(scaled_font), scaled_font, (char *)utf8, utf8_len, glyphs, num_glyphs, clusters, num_clusters, cluster_flags);
+}
+
+// go_cairo_user_font_face_create creates a user font face calling
+// the Go callbacks of funcs, optional callbacks are only set if has_* is true.
+// The handle gets deleted when the font face is destroyed.
+static cairo_font_face_t *go_cairo_user_font_face_create(uintptr_t funcs, int has_init, int has_unicode_to_glyph, int has_text_to_glyphs) {
+	cairo_font_face_t *font_face = cairo_user_font_face_create();
+	if (cairo_font_face_set_user_data(font_face, &go_cairo_user_font_key, (void *)funcs, go_cairo_user_font_delete_handle) != CAIRO_STATUS_SUCCESS) {
+		go_cairo_user_font_delete_handle((void *)funcs);
+		return font_face;
+	}
+	if (has_init) {
+		cairo_user_font_face_set_init_func(font_face, go_cairo_user_font_init);
+	}
+	cairo_user_font_face_set_render_glyph_func(font_face, go_cairo_user_font_render_glyph);
+	if (has_unicode_to_glyph) {
+		cairo_user_font_face_set_unicode_to_glyph_func(font_face, go_cairo_user_font_unicode_to_glyph);
+	}
+	if (has_text_to_glyphs) {
+		cairo_user_font_face_set_text_to_glyphs_func(font_face, go_cairo_user_font_text_to_glyphs);
+	}
+	return font_face;
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"runtime/cgo"
+)
+
+// UserFontInitFunc is called once for every new scaled font of a user font face.
+// context can be used to query the scale of the font, extents holds the
+// default font extents in font-space and can be changed.
+type UserFontInitFunc func(scaledFont *ScaledFont, context *Context, extents *FontExtents) error
+
+// UserFontRenderGlyphFunc draws glyph on context in font-space
+// with the source color set by the caller of cairo.
+// The ink extents of the drawing are calculated by cairo,
+// only the advance values of extents should be set.
+type UserFontRenderGlyphFunc func(scaledFont *ScaledFont, glyph uint64, context *Context, extents *TextExtents) error
+
+// UserFontUnicodeToGlyphFunc returns the glyph index for unicode.
+type UserFontUnicodeToGlyphFunc func(scaledFont *ScaledFont, unicode rune) (glyph uint64, err error)
+
+// UserFontTextToGlyphsFunc converts text to glyphs positioned in font-space
+// and the clusters mapping the bytes of text to the glyphs.
+// Returning STATUS_USER_FONT_NOT_IMPLEMENTED falls back to UnicodeToGlyph.
+type UserFontTextToGlyphsFunc func(scaledFont *ScaledFont, text string) (glyphs []Glyph, clusters []TextCluster, flags TextClusterFlag, err error)
+
+// UserFontFuncs are the Go callbacks of a user font face.
+// Only RenderGlyph is required. Without UnicodeToGlyph and TextToGlyphs
+// unicode characters are used as glyph indices.
+//
+// The ScaledFont and Context arguments of the callbacks are only valid
+// during the call and must not be destroyed.
+// A callback returning a Status as error fails with that status,
+// other errors fail with STATUS_USER_FONT_ERROR.
+type UserFontFuncs struct {
+	Init           UserFontInitFunc
+	RenderGlyph    UserFontRenderGlyphFunc
+	UnicodeToGlyph UserFontUnicodeToGlyphFunc
+	TextToGlyphs   UserFontTextToGlyphsFunc
+}
+
+// NewUserFontFace creates a font face of FONT_TYPE_USER
+// that draws its glyphs with Go callbacks.
+// The callbacks may be called from other threads than the one drawing.
+func NewUserFontFace(funcs UserFontFuncs) (*FontFace, error) {
+	if funcs.RenderGlyph == nil {
+		return nil, errors.New("cairo.NewUserFontFace(): RenderGlyph is nil")
+	}
+	handle := cgo.NewHandle(&funcs)
+	face := C.go_cairo_user_font_face_create(C.uintptr_t(handle),
+		cBool(funcs.Init != nil),
+		cBool(funcs.UnicodeToGlyph != nil),
+		cBool(funcs.TextToGlyphs != nil),
+	)
+	fontFace := newFontFace(face)
+	if err := statusError(fontFace.Status()); err != nil {
+		fontFace.Destroy()
+		return nil, err
+	}
+	return fontFace, nil
+}
+
+func cBool(b bool) C.int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// callbackStatus converts the error of a Go callback to a cairo status.
+func callbackStatus(err error) Status {
+	if err == nil {
+		return STATUS_SUCCESS
+	}
+	var status Status
+	if errors.As(err, &status) && status != STATUS_SUCCESS {
+		return status
+	}
+	return STATUS_USER_FONT_ERROR
+}
+
+func (self *UserFontFuncs) init(scaledFont *ScaledFont, context *Context, cfe *C.cairo_font_extents_t) Status {
+	extents := newFontExtents(cfe)
+	if err := self.Init(scaledFont, context, extents); err != nil {
+		return callbackStatus(err)
+	}
+	cfe.ascent = C.double(extents.Ascent)
+	cfe.descent = C.double(extents.Descent)
+	cfe.height = C.double(extents.Height)
+	cfe.max_x_advance = C.double(extents.MaxXadvance)
+	cfe.max_y_advance = C.double(extents.MaxYadvance)
+	return STATUS_SUCCESS
+}
+
+func (self *UserFontFuncs) renderGlyph(scaledFont *ScaledFont, glyph uint64, context *Context, cte *C.cairo_text_extents_t) Status {
+	extents := newTextExtents(cte)
+	if err := self.RenderGlyph(scaledFont, glyph, context, extents); err != nil {
+		return callbackStatus(err)
+	}
+	cte.x_bearing = C.double(extents.Xbearing)
+	cte.y_bearing = C.double(extents.Ybearing)
+	cte.width = C.double(extents.Width)
+	cte.height = C.double(extents.Height)
+	cte.x_advance = C.double(extents.Xadvance)
+	cte.y_advance = C.double(extents.Yadvance)
+	return STATUS_SUCCESS
+}
+
+// textToGlyphs stores the result of the TextToGlyphs callback in the
+// arrays provided by cairo, or allocates new ones if they are too small.
+// clusters is nil if cairo does not need clusters.
+func (self *UserFontFuncs) textToGlyphs(scaledFont *ScaledFont, text string, glyphs **C.cairo_glyph_t, numGlyphs *C.int, clusters **C.cairo_text_cluster_t, numClusters *C.int, flags *C.cairo_text_cluster_flags_t) Status {
+	outGlyphs, outClusters, outFlags, err := self.TextToGlyphs(scaledFont, text)
+	if err != nil {
+		return callbackStatus(err)
+	}
+
+	if *glyphs == nil || int(*numGlyphs) < len(outGlyphs) {
+		*glyphs = C.cairo_glyph_allocate(C.int(len(outGlyphs)))
+		if *glyphs == nil && len(outGlyphs) > 0 {
+			return STATUS_NO_MEMORY
+		}
+	}
+	copy(unsafeGlyphs(*glyphs, len(outGlyphs)), cairoGlyphs(outGlyphs))
+	*numGlyphs = C.int(len(outGlyphs))
+
+	if clusters != nil {
+		if *clusters == nil || int(*numClusters) < len(outClusters) {
+			*clusters = C.cairo_text_cluster_allocate(C.int(len(outClusters)))
+			if *clusters == nil && len(outClusters) > 0 {
+				return STATUS_NO_MEMORY
+			}
+		}
+		copy(unsafeTextClusters(*clusters, len(outClusters)), cairoTextClusters(outClusters))
+		*numClusters = C.int(len(outClusters))
+		*flags = C.cairo_text_cluster_flags_t(outFlags)
+	}
+	return STATUS_SUCCESS
+}
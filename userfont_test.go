@@ -0,0 +1,176 @@
+//go:build !goci
+// +build !goci
+
+package cairo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+var errUnknownRune = errors.New("unknown rune")
+
+// testUserFontFuncs returns a font mapping 'a' to 'z' to glyphs 1 to 26.
+// Glyph g is a 0.1 wide bar with an advance of 0.2*g in font-space.
+func testUserFontFuncs(rendered map[uint64]bool) UserFontFuncs {
+	return UserFontFuncs{
+		RenderGlyph: func(scaledFont *ScaledFont, glyph uint64, context *Context, extents *TextExtents) error {
+			if rendered != nil {
+				rendered[glyph] = true
+			}
+			context.Rectangle(0, -0.5, 0.1, 0.5)
+			context.Fill()
+			extents.Xadvance = 0.2 * float64(glyph)
+			return nil
+		},
+		UnicodeToGlyph: func(scaledFont *ScaledFont, unicode rune) (uint64, error) {
+			if unicode < 'a' || unicode > 'z' {
+				return 0, errUnknownRune
+			}
+			return uint64(unicode-'a') + 1, nil
+		},
+	}
+}
+
+// newUserFontContext returns a context drawing on a new surface
+// with a user font face of funcs at size 10 and the baseline at y = 10.
+func newUserFontContext(t *testing.T, funcs UserFontFuncs, width int) *Context {
+	t.Helper()
+	fontFace, err := NewUserFontFace(funcs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	surface := NewSurface(FORMAT_ARGB32, width, 12)
+	context := NewContext(surface)
+	surface.Destroy()
+	context.SetFontFace(fontFace)
+	fontFace.Destroy()
+	context.SetFontSize(10)
+	context.Translate(0, 10)
+	context.MoveTo(0, 0)
+	return context
+}
+
+func TestUserFontUnicodeToGlyph(t *testing.T) {
+	rendered := make(map[uint64]bool)
+	context := newUserFontContext(t, testUserFontFuncs(rendered), 16)
+	defer context.Destroy()
+
+	tests := []struct {
+		text     string
+		xadvance float64
+	}{
+		{"a", 2},
+		{"abc", 12},
+		{"cab", 12},
+		{"z", 52},
+	}
+	for _, test := range tests {
+		extents := context.TextExtents(test.text)
+		if !nearlyEqual(extents.Xadvance, test.xadvance, 1e-6) {
+			t.Errorf("TextExtents(%q).Xadvance is %v, expected %v", test.text, extents.Xadvance, test.xadvance)
+		}
+		if extents.Width <= 0 {
+			t.Errorf("TextExtents(%q).Width is %v, expected ink", test.text, extents.Width)
+		}
+		scaledFont := context.GetScaledFont()
+		if extents := scaledFont.TextExtents(test.text); !nearlyEqual(extents.Xadvance, test.xadvance, 1e-6) {
+			t.Errorf("ScaledFont.TextExtents(%q).Xadvance is %v, expected %v", test.text, extents.Xadvance, test.xadvance)
+		}
+		scaledFont.Destroy()
+	}
+
+	context.SetSourceRGB(0, 0, 0)
+	if err := context.ShowText("abc"); err != nil {
+		t.Fatal(err)
+	}
+	for glyph := uint64(1); glyph <= 3; glyph++ {
+		if !rendered[glyph] {
+			t.Errorf("glyph %d was not rendered", glyph)
+		}
+	}
+	// The bars of a, b and c start at the advances 0, 2 and 6
+	img := context.GetTarget().GetImage()
+	for x := 0; x < 12; x++ {
+		_, _, _, a := img.At(x, 7).RGBA()
+		if painted, expected := a != 0, x == 0 || x == 2 || x == 6; painted != expected {
+			t.Errorf("pixel %d, 7 has alpha %#x, expected painted %v", x, a, expected)
+		}
+	}
+}
+
+func TestUserFontTextToGlyphs(t *testing.T) {
+	// More glyphs than fit in the stack buffers cairo
+	// passes to text_to_glyphs, so they have to be reallocated.
+	const numGlyphs = 300
+	funcs := testUserFontFuncs(nil)
+	funcs.TextToGlyphs = func(scaledFont *ScaledFont, text string) ([]Glyph, []TextCluster, TextClusterFlag, error) {
+		glyphs := make([]Glyph, len(text))
+		clusters := make([]TextCluster, len(text))
+		for i := range text {
+			glyphs[i] = Glyph{Index: 1, X: 0.2 * float64(i)}
+			clusters[i] = TextCluster{NumBytes: 1, NumGlyphs: 1}
+		}
+		return glyphs, clusters, 0, nil
+	}
+	context := newUserFontContext(t, funcs, 16)
+	defer context.Destroy()
+
+	text := strings.Repeat("a", numGlyphs)
+	if extents := context.TextExtents(text); !nearlyEqual(extents.Xadvance, 2*numGlyphs, 1e-6) {
+		t.Errorf("TextExtents().Xadvance is %v, expected %v", extents.Xadvance, 2*numGlyphs)
+	}
+	if err := context.ShowText(text); err != nil {
+		t.Errorf("ShowText() returned %v", err)
+	}
+	if status := context.Status(); status != STATUS_SUCCESS {
+		t.Errorf("Status() is %s", status)
+	}
+}
+
+func TestUserFontErrors(t *testing.T) {
+	statusErr := fmt.Errorf("glyph 2 is broken: %w", STATUS_INVALID_CLUSTERS)
+	tests := []struct {
+		name     string
+		funcs    func(funcs *UserFontFuncs)
+		text     string
+		expected Status
+	}{
+		{"unicode to glyph error", func(funcs *UserFontFuncs) {}, "a?", STATUS_USER_FONT_ERROR},
+		{"render glyph status", func(funcs *UserFontFuncs) {
+			render := funcs.RenderGlyph
+			funcs.RenderGlyph = func(scaledFont *ScaledFont, glyph uint64, context *Context, extents *TextExtents) error {
+				if glyph == 2 {
+					return statusErr
+				}
+				return render(scaledFont, glyph, context, extents)
+			}
+		}, "ab", STATUS_INVALID_CLUSTERS},
+		{"text to glyphs error", func(funcs *UserFontFuncs) {
+			funcs.TextToGlyphs = func(scaledFont *ScaledFont, text string) ([]Glyph, []TextCluster, TextClusterFlag, error) {
+				return nil, nil, 0, errors.New("no shaping")
+			}
+		}, "a", STATUS_USER_FONT_ERROR},
+	}
+	for _, test := range tests {
+		funcs := testUserFontFuncs(nil)
+		test.funcs(&funcs)
+		context := newUserFontContext(t, funcs, 16)
+		context.TextExtents(test.text)
+
+		if status := context.Status(); status != test.expected {
+			t.Errorf("%s: Status() is %s, expected %s", test.name, status, test.expected)
+		}
+		scaledFont := context.GetScaledFont()
+		if status := scaledFont.Status(); status != test.expected {
+			t.Errorf("%s: font Status() is %s, expected %s", test.name, status, test.expected)
+		}
+		scaledFont.Destroy()
+		if err := context.ShowText(test.text); !errors.Is(err, test.expected) {
+			t.Errorf("%s: ShowText() returned %v, expected %s", test.name, err, test.expected)
+		}
+		context.Destroy()
+	}
+}